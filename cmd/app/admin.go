@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"DragonTUI/internal/db"
+)
+
+const adminUsage = `usage: app admin <command>
+
+commands:
+  list                         list the keys authorized as admins
+  add <fingerprint> [name...]  authorize a key, e.g. SHA256:...
+  remove <fingerprint>         revoke a key`
+
+func runAdmin(dbPath string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(adminUsage)
+	}
+
+	database, err := db.InitDatabase(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "list":
+		admins, err := database.ListAdmins(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FINGERPRINT\tNAME\tADDED")
+		for _, a := range admins {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", a.Fingerprint, a.Name, a.CreatedAt.Format(time.RFC3339))
+		}
+		return w.Flush()
+
+	case "add":
+		if len(args) < 2 || !strings.HasPrefix(args[1], "SHA256:") {
+			return fmt.Errorf("add takes a SHA256 key fingerprint\n%s", adminUsage)
+		}
+		if err := database.AddAdmin(ctx, args[1], strings.Join(args[2:], " ")); err != nil {
+			return err
+		}
+		fmt.Printf("authorized %s\n", args[1])
+
+	case "remove":
+		if len(args) < 2 {
+			return fmt.Errorf("remove takes a key fingerprint\n%s", adminUsage)
+		}
+		if err := database.RemoveAdmin(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("revoked %s\n", args[1])
+
+	default:
+		return fmt.Errorf("unknown admin command %q\n%s", args[0], adminUsage)
+	}
+	return nil
+}
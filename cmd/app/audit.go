@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"DragonTUI/internal/db"
+)
+
+const auditUsage = `usage: app audit <command>
+
+commands:
+  list [flags]  list the latest entries, oldest first
+
+Actions are connect, disconnect, navigate, contact and admin.*, so
+-action admin matches every admin action. See app audit list -h for flags.`
+
+func runAudit(dbPath string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(auditUsage)
+	}
+	if args[0] != "list" {
+		return fmt.Errorf("unknown audit command %q\n%s", args[0], auditUsage)
+	}
+
+	set := flag.NewFlagSet("audit list", flag.ContinueOnError)
+	set.Usage = func() {
+		fmt.Fprintln(set.Output(), "usage: app audit list [flags]")
+		set.PrintDefaults()
+	}
+	var q db.AuditQuery
+	set.StringVar(&q.Session, "session", "", "only entries of this session ID")
+	set.StringVar(&q.Fingerprint, "key", "", "only entries of this key fingerprint")
+	set.StringVar(&q.Action, "action", "", "only this action, or actions under it")
+	since := set.Duration("since", 0, "only entries this recent, e.g. 24h")
+	set.IntVar(&q.Limit, "n", 50, "how many entries to show, 0 for all")
+	if err := set.Parse(args[1:]); err != nil {
+		return err
+	}
+	if set.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q\n%s", set.Arg(0), auditUsage)
+	}
+	if *since > 0 {
+		q.Since = time.Now().Add(-*since)
+	}
+
+	database, err := db.InitDatabase(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	entries, err := database.ListAudit(ctx, q)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSESSION\tADDRESS\tKEY\tACTION\tDETAIL")
+	for _, e := range entries {
+		key := e.Fingerprint
+		if key == "" {
+			key = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.CreatedAt.Local().Format(time.DateTime), e.Session, e.RemoteAddr, key, e.Action, e.Detail)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"DragonTUI/internal/db"
+)
+
+const blocklistUsage = `usage: app blocklist <command>
+
+commands:
+  list                                    list blocked senders
+  add <email|key|ip> <value> [reason...]  refuse contact messages from a sender
+  remove <email|key|ip> <value>           lift a block`
+
+func parseBlockKind(s string) (db.BlockKind, error) {
+	switch kind := db.BlockKind(s); kind {
+	case db.BlockEmail, db.BlockKey, db.BlockIP:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown kind %q, want email, key or ip\n%s", s, blocklistUsage)
+	}
+}
+
+func runBlocklist(dbPath string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(blocklistUsage)
+	}
+
+	database, err := db.InitDatabase(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "list":
+		entries, err := database.ListBlocked(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tVALUE\tREASON\tADDED")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Kind, e.Value, e.Reason, e.CreatedAt.Format(time.RFC3339))
+		}
+		return w.Flush()
+
+	case "add", "remove":
+		if len(args) < 3 {
+			return fmt.Errorf("%s takes a kind and a value\n%s", args[0], blocklistUsage)
+		}
+		kind, err := parseBlockKind(args[1])
+		if err != nil {
+			return err
+		}
+		if args[0] == "remove" {
+			if err := database.Unblock(ctx, kind, args[2]); err != nil {
+				return err
+			}
+			fmt.Printf("unblocked %s %s\n", kind, args[2])
+			return nil
+		}
+		if err := database.Block(ctx, kind, args[2], strings.Join(args[3:], " ")); err != nil {
+			return err
+		}
+		fmt.Printf("blocked %s %s\n", kind, args[2])
+
+	default:
+		return fmt.Errorf("unknown blocklist command %q\n%s", args[0], blocklistUsage)
+	}
+	return nil
+}
@@ -1,88 +1,601 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
+	"DragonTUI/internal/abuse"
+	"DragonTUI/internal/config"
+	"DragonTUI/internal/content"
 	"DragonTUI/internal/db"
+	"DragonTUI/internal/health"
+	"DragonTUI/internal/logging"
+	"DragonTUI/internal/mail"
+	"DragonTUI/internal/metrics"
 	"DragonTUI/internal/pages"
+	"DragonTUI/internal/projects"
 	"DragonTUI/internal/server"
+	"DragonTUI/internal/softserve"
+	"DragonTUI/internal/theme"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
-	"github.com/joho/godotenv"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/muesli/termenv"
 )
 
 type appModel struct {
-	term          string
-	currentPage   pages.Page
+	ctx           context.Context
+	router        *pages.Router
 	lastWindowMsg tea.WindowSizeMsg
+	initCmd       tea.Cmd
+	sources       []content.Notifier
+	// sessions is told which page this session is on and how big its
+	// window is, for the admin sessions page.
+	sessions  *server.Registry
+	sessionID uint64
+	metrics   *metrics.Metrics
+	audit     *server.Auditor
+	// themes are what ctrl+t cycles through.
+	themes *theme.Set
+	// renderer draws the session's styles. profile is the color profile
+	// detected for its terminal, which plain mode overrides.
+	renderer *lipgloss.Renderer
+	profile  termenv.Profile
+	// notice is the admin notice shown above the page, if any. noticeSeq
+	// makes sure only the latest notice's timer clears it.
+	notice    string
+	noticeSeq int
+	// shutdownAt is when the server closes this session, zero unless it is
+	// shutting down.
+	shutdownAt time.Time
+}
+
+// noticeDuration is how long a broadcast notice stays on screen.
+const noticeDuration = 15 * time.Second
+
+type noticeExpiredMsg struct {
+	seq int
+}
+
+// shutdownTickMsg redraws the shutdown countdown.
+type shutdownTickMsg struct{}
+
+func shutdownTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return shutdownTickMsg{} })
 }
 
 func (m *appModel) Init() tea.Cmd {
-	if m.currentPage != nil {
-		return m.currentPage.Init()
+	cmds := []tea.Cmd{m.initCmd}
+	for _, source := range m.sources {
+		cmds = append(cmds, pages.WaitForContent(m.ctx, source))
 	}
-	return nil
+	return tea.Batch(cmds...)
 }
 
 func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.lastWindowMsg = msg
+		m.sessions.SetWindow(m.sessionID, msg.Width, msg.Height)
+	case pages.ContentUpdatedMsg:
+		cmds = append(cmds, pages.WaitForContent(m.ctx, msg.Source))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+t":
+			next := m.themes.Next(m.router.Theme()).For(m.renderer)
+			return m, tea.Batch(m.router.SetTheme(next), m.showNotice("Theme: "+next.Name))
+		case "ctrl+o":
+			return m, m.togglePlain()
+		}
+	case pages.NoticeMsg:
+		return m, m.showNotice(msg.Text)
+	case noticeExpiredMsg:
+		if msg.seq == m.noticeSeq {
+			m.notice = ""
+		}
+		return m, nil
+	case server.ShutdownMsg:
+		m.shutdownAt = msg.Deadline
+		return m, shutdownTick()
+	case shutdownTickMsg:
+		if time.Until(m.shutdownAt) > 0 {
+			return m, shutdownTick()
+		}
+		return m, nil
 	}
 
-	if m.currentPage == nil {
-		return m, tea.Quit
-	}
+	prev := m.router.CurrentRoute()
+	cmds = append(cmds, m.router.Update(msg))
 
-	page, cmd := m.currentPage.Update(msg)
-	m.currentPage = page
+	if route := string(m.router.CurrentRoute()); route != string(prev) {
+		m.sessions.SetPage(m.sessionID, route)
+		m.metrics.PageView(route)
+		audit := m.audit
+		cmds = append(cmds, func() tea.Msg {
+			audit.Record("navigate", route)
+			return nil
+		})
+		windowMsg := m.lastWindowMsg
+		cmds = append(cmds, func() tea.Msg { return windowMsg })
+	}
+	return m, tea.Batch(cmds...)
+}
 
-	if m.currentPage != page {
-		return m, tea.Batch(cmd, func() tea.Msg { return m.lastWindowMsg })
+// togglePlain switches plain mode, which draws every page without color or
+// box drawing, and redraws the pages.
+func (m *appModel) togglePlain() tea.Cmd {
+	if m.profile == termenv.Ascii {
+		return m.showNotice("This terminal has no colors, so it is always in plain mode")
+	}
+	notice := "Plain mode on"
+	if m.renderer.ColorProfile() == termenv.Ascii {
+		m.renderer.SetColorProfile(m.profile)
+		notice = "Plain mode off"
+	} else {
+		m.renderer.SetColorProfile(termenv.Ascii)
 	}
-	return m, cmd
+	return tea.Batch(m.router.SetTheme(m.router.Theme()), m.showNotice(notice))
+}
+
+// showNotice puts text above the page until the next notice or until
+// noticeDuration has passed.
+func (m *appModel) showNotice(text string) tea.Cmd {
+	m.notice = text
+	m.noticeSeq++
+	seq := m.noticeSeq
+	return tea.Tick(noticeDuration, func(time.Time) tea.Msg { return noticeExpiredMsg{seq: seq} })
 }
 
 func (m *appModel) View() string {
-	if m.currentPage != nil {
-		return m.currentPage.View()
+	page := m.router.Current()
+	if page == nil {
+		return "Goodbye!"
+	}
+	if width, height := m.lastWindowMsg.Width, m.lastWindowMsg.Height; pages.TooSmall(width, height) {
+		return pages.RenderTooSmall(m.router.Theme(), width, height)
+	}
+	start := time.Now()
+	view := page.View()
+	m.metrics.ObserveRender(string(m.router.CurrentRoute()), time.Since(start))
+	notice := m.notice
+	if !m.shutdownAt.IsZero() {
+		in := "a moment"
+		if seconds := int(time.Until(m.shutdownAt).Round(time.Second).Seconds()); seconds > 1 {
+			in = fmt.Sprintf("%d seconds", seconds)
+		}
+		notice = fmt.Sprintf("The server is restarting in %s. Messages you have sent are safe; come back in a minute!", in)
+	}
+	if notice == "" {
+		return view
+	}
+	// Pages fill the whole window, so the notice takes the place of their
+	// first line rather than pushing the bottom one off screen.
+	banner := pages.RenderNotice(m.router.Theme(), notice, m.lastWindowMsg.Width)
+	if _, rest, ok := strings.Cut(view, "\n"); ok {
+		return banner + "\n" + rest
+	}
+	return banner
+}
+
+// app holds the process-wide dependencies shared by every SSH session.
+type app struct {
+	cfg    *config.Config
+	db     *db.Database
+	queue  pages.MailQueue
+	mailer mail.Mailer
+	admins *server.Admins
+	// sessions tracks every open session for the admin pages.
+	sessions    *server.Registry
+	submissions *pages.SubmissionLimit
+	filter      abuse.Filter
+	// metrics is nil when metrics are turned off.
+	metrics *metrics.Metrics
+	resume  *content.Store
+	docs    *content.Library
+	index   *content.Index
+	// projects is nil when the Projects page is disabled.
+	projects projects.Source
+	themes   *theme.Set
+	// theme is what sessions start in, and lightTheme what they start in
+	// on terminals with a light background.
+	theme      *theme.Theme
+	lightTheme *theme.Theme
+}
+
+func (a *app) newRouter(visitor pages.Visitor, audit *server.Auditor, th *theme.Theme, width, height int) *pages.Router {
+	router := pages.NewRouter(width, height, th)
+	router.Register(pages.RouteMenu, func(w, h int) pages.Page {
+		return pages.NewMenuModel(w, h, visitor, a.projects != nil, a.db, a.sessions)
+	})
+	router.Register(pages.RouteAbout, func(w, h int) pages.Page { return pages.NewAboutModel(w, h, a.resume) })
+	router.Register(pages.RouteContact, func(w, h int) pages.Page {
+		return pages.NewContactModel(w, h, visitor, pages.ContactOptions{
+			Store:     a.db,
+			Mailer:    a.mailer,
+			Queue:     a.queue,
+			Limit:     a.submissions,
+			Filter:    a.filter,
+			Challenge: a.cfg.Abuse.Challenge,
+			Work:      a.sessions,
+			Observer:  a.metrics,
+			Audit:     audit,
+		})
+	})
+	router.Register(pages.RouteDocs, func(w, h int) pages.Page { return pages.NewDocsModel(w, h, a.docs) })
+	router.Register(pages.RouteDoc, func(w, h int) pages.Page { return pages.NewDocModel(w, h, a.docs) })
+	router.Register(pages.RouteSearch, func(w, h int) pages.Page { return pages.NewSearchModel(w, h, a.index) })
+	if a.projects != nil {
+		router.Register(pages.RouteProjects, func(w, h int) pages.Page {
+			return pages.NewProjectsModel(w, h, a.projects)
+		})
+		if browser, ok := a.projects.(pages.RepoBrowser); ok {
+			router.Register(pages.RouteRepo, func(w, h int) pages.Page {
+				return pages.NewRepoModel(w, h, browser)
+			})
+		}
+	}
+	if visitor.Admin {
+		router.Register(pages.RouteAdmin, func(w, h int) pages.Page { return pages.NewAdminModel(w, h) })
+		router.Register(pages.RouteAdminSessions, func(w, h int) pages.Page {
+			return pages.NewAdminSessionsModel(w, h, a.sessions, audit)
+		})
+		router.Register(pages.RouteAdminMenu, func(w, h int) pages.Page {
+			return pages.NewAdminMenuModel(w, h, a.db, a.sessions, audit, a.projects != nil)
+		})
+		router.Register(pages.RouteAdminBroadcast, func(w, h int) pages.Page {
+			return pages.NewAdminBroadcastModel(w, h, a.sessions, audit)
+		})
+		router.Register(pages.RouteInbox, func(w, h int) pages.Page {
+			return pages.NewInboxModel(w, h, a.db, a.queue, audit)
+		})
 	}
-	return "Goodbye!"
+	return router
 }
 
-func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+func (a *app) teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 	pty, _, _ := s.Pty()
 
-	initPage := pages.NewMenuModel(pty.Window.Width, pty.Window.Height)
+	id := server.SessionIdentity(s)
+	visitor := pages.Visitor{
+		User:           s.User(),
+		KeyFingerprint: id.Fingerprint,
+		RemoteAddr:     s.RemoteAddr().String(),
+		Admin:          a.admins.IsAdmin(s.Context(), id.Fingerprint),
+		Returning:      id.Returning(),
+		Session:        server.CorrelationID(s),
+	}
+	if id.User != nil {
+		visitor.UserID = id.User.ID
+		visitor.Name = id.User.Name
+	}
+
+	renderer, profile := newRenderer(s)
+	th := a.theme
+	if !renderer.HasDarkBackground() {
+		th = a.lightTheme
+	}
+
+	audit := server.SessionAuditor(s)
+	router := a.newRouter(visitor, audit, th.For(renderer), pty.Window.Width, pty.Window.Height)
+	initCmd, err := router.Start(pages.RouteMenu)
+	if err != nil {
+		wish.Fatalln(s, err)
+		return nil, nil
+	}
+
+	sessionID := server.SessionID(s)
+	a.sessions.SetPage(sessionID, string(router.CurrentRoute()))
+	a.metrics.PageView(string(router.CurrentRoute()))
 
 	app := &appModel{
-		term:        pty.Term,
-		currentPage: initPage,
+		ctx:       s.Context(),
+		router:    router,
+		initCmd:   initCmd,
+		sources:   []content.Notifier{a.resume, a.docs, a.index},
+		sessions:  a.sessions,
+		sessionID: sessionID,
+		metrics:   a.metrics,
+		audit:     audit,
+		themes:    a.themes,
+		renderer:  renderer,
+		profile:   profile,
+		lastWindowMsg: tea.WindowSizeMsg{
+			Width:  pty.Window.Width,
+			Height: pty.Window.Height,
+		},
 	}
 	return app, []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithOutput(os.Stderr)}
 }
 
+// newRenderer returns a renderer for the session's terminal, with the color
+// profile detected from its TERM and COLORTERM and its background queried.
+// Clients that send NO_COLOR start in plain mode.
+func newRenderer(s ssh.Session) (*lipgloss.Renderer, termenv.Profile) {
+	r := bubbletea.MakeRenderer(s)
+	profile := r.ColorProfile()
+	for _, kv := range s.Environ() {
+		if name, value, _ := strings.Cut(kv, "="); name == "NO_COLOR" && value != "" {
+			r.SetColorProfile(termenv.Ascii)
+		}
+	}
+	return r, profile
+}
+
+func newMailer(cfg config.MailConfig) (mail.Mailer, error) {
+	if cfg.Backend == config.MailDisabled {
+		return nil, nil
+	}
+	return mail.New(mail.Config{
+		Backend:      mail.Backend(cfg.Backend),
+		From:         cfg.From,
+		To:           cfg.To,
+		ResendAPIKey: cfg.ResendAPIKey,
+		SMTPHost:     cfg.SMTP.Host,
+		SMTPPort:     cfg.SMTP.Port,
+		SMTPUsername: cfg.SMTP.Username,
+		SMTPPassword: cfg.SMTP.Password,
+		SMTPStartTLS: cfg.SMTP.StartTLS,
+		MaildirPath:  cfg.MaildirPath,
+	})
+}
+
+// newHealth sets up the readiness checks served at /readyz.
+func (a *app) newHealth() *health.Server {
+	h := health.New()
+	h.Add("ssh", func(ctx context.Context) (map[string]string, error) {
+		details := map[string]string{"sessions": strconv.Itoa(a.sessions.Count())}
+		if !a.sessions.Serving() {
+			return details, errors.New("the SSH server is not taking connections")
+		}
+		return details, nil
+	})
+	h.Add("database", a.db.CheckHealth)
+	h.Add("mail", func(ctx context.Context) (map[string]string, error) {
+		details := map[string]string{"backend": a.cfg.Mail.Backend}
+		if a.cfg.Mail.Backend == config.MailDisabled {
+			return details, nil
+		}
+		if a.queue == nil {
+			return details, errors.New("mail delivery is not set up")
+		}
+		if counts, err := a.db.OutboxCounts(ctx); err == nil {
+			details["queued"] = strconv.Itoa(counts.Queued)
+			details["failed"] = strconv.Itoa(counts.Failed)
+		}
+		return details, nil
+	})
+	h.Add("content", func(ctx context.Context) (map[string]string, error) {
+		details := map[string]string{"documents": strconv.Itoa(len(a.docs.Documents()))}
+		// A document that doesn't parse is left out of the Writing page,
+		// which still works, so it is reported without failing the check.
+		if err := a.docs.Err(); err != nil {
+			details["documents_error"] = err.Error()
+		}
+		resume := a.resume.Current()
+		if resume.Err != nil {
+			return details, fmt.Errorf("failed to load resume: %w", resume.Err)
+		}
+		if resume.Version == 0 {
+			return details, errors.New("the resume hasn't loaded yet")
+		}
+		details["resume_loaded_at"] = resume.LoadedAt.Format(time.RFC3339)
+		return details, nil
+	})
+	return h
+}
+
+func newProjectSource(cfg config.ProjectsConfig, hostKeyPath string) (projects.Source, error) {
+	switch cfg.Source {
+	case config.ProjectsManifest:
+		return projects.NewManifest(cfg.Manifest), nil
+	case config.ProjectsSoftServe:
+		keyPath := cfg.SoftServe.KeyPath
+		if keyPath == "" {
+			keyPath = hostKeyPath
+		}
+		client, err := softserve.NewClient(softserve.Config{
+			Addr:                cfg.SoftServe.Addr,
+			User:                cfg.SoftServe.User,
+			KeyPath:             keyPath,
+			KnownHostsPath:      cfg.SoftServe.KnownHosts,
+			InsecureSkipHostKey: cfg.SoftServe.InsecureSkipHostKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return softserve.NewSource(client), nil
+	default:
+		return nil, nil
+	}
+}
+
+// commands are the subcommands run instead of the server, given the
+// database path and the arguments after the command's name.
+var commands = map[string]func(dbPath string, args []string) error{
+	"migrate":   runMigrate,
+	"admin":     runAdmin,
+	"blocklist": runBlocklist,
+	"audit":     runAudit,
+	"resume":    runResume,
+}
+
 func main() {
-	err := godotenv.Load()
+	// The global flags may come before or after the command's name, as in
+	// "app -db x.db migrate up" or "app migrate -db x.db up".
+	args := os.Args[1:]
+	command := ""
+	if len(args) > 0 && commands[args[0]] != nil {
+		command, args = args[0], args[1:]
+	}
+
+	cfg, rest, err := config.Load(filepath.Base(os.Args[0]), args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if cfg == nil {
+		log.Fatal("Invalid configuration", "err", err)
+	}
+	if command == "" && len(rest) > 0 {
+		if commands[rest[0]] == nil {
+			log.Fatal("Unknown command", "command", rest[0], "commands", "migrate, admin, blocklist, audit, resume")
+		}
+		command, rest = rest[0], rest[1:]
+	}
+
+	if command != "" {
+		if err != nil {
+			log.Fatalf("Invalid configuration:\n%v", err)
+		}
+		if err := commands[command](cfg.Database.Path, rest); err != nil && !errors.Is(err, flag.ErrHelp) {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := errors.Join(err, cfg.Validate()); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	_, closeLog, err := logging.Setup(cfg.Log)
 	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
+		log.Fatal("Failed to set up logging", "err", err)
 	}
-	dburl := os.Getenv("DB_URL")
-	appPort, _ := strconv.Atoi(os.Getenv("APP_PORT"))
-	appHost := os.Getenv("APP_HOST")
+	defer closeLog()
 
-	db, err := db.InitDatabase(dburl)
+	mailer, err := newMailer(cfg.Mail)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		log.Fatal("Failed to set up mail delivery", "err", err)
 	}
-	f, err := tea.LogToFile("debug.log", "debug")
-	defer db.Close()
+
+	themes, err := theme.Load(cfg.Theme.Dir)
+	if err != nil {
+		log.Fatal("Failed to load themes", "err", err)
+	}
+	defaultTheme, ok := themes.Get(cfg.Theme.Default)
+	if !ok {
+		log.Fatal("Unknown theme", "theme", cfg.Theme.Default, "themes", strings.Join(themes.Names(), ", "))
+	}
+	lightTheme, ok := themes.Get(cfg.Theme.Light)
+	if !ok {
+		log.Fatal("Unknown light theme", "theme", cfg.Theme.Light, "themes", strings.Join(themes.Names(), ", "))
+	}
+
+	projectSource, err := newProjectSource(cfg.Projects, cfg.Server.HostKeyPath)
 	if err != nil {
-		log.Fatalf("err: %v", err)
+		log.Fatal("Failed to set up projects", "err", err)
 	}
-	defer f.Close()
-	server.InitServer(appHost, appPort, teaHandler)
+
+	db, err := db.InitDatabase(cfg.Database.Path)
+	if err != nil {
+		log.Fatal("Failed to initialize database", "err", err)
+	}
+	defer db.Close()
+
+	a := &app{
+		cfg:      cfg,
+		db:       db,
+		mailer:   mailer,
+		admins:   server.NewAdmins(cfg.Admin.Fingerprints, cfg.Admin.AuthorizedFile, db),
+		sessions: server.NewRegistry(),
+		submissions: &pages.SubmissionLimit{
+			Store:  db,
+			Limit:  cfg.RateLimit.Submissions,
+			Window: cfg.RateLimit.SubmissionWindow,
+		},
+		filter: abuse.Pipeline{
+			abuse.Blocklist(db),
+			abuse.Duplicates(db, cfg.Abuse.DuplicateWindow),
+			abuse.Heuristics(cfg.Abuse.MaxLinks, cfg.Abuse.Keywords),
+		},
+		projects:   projectSource,
+		themes:     themes,
+		theme:      defaultTheme,
+		lightTheme: lightTheme,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if cfg.Metrics.Addr != "" {
+		a.metrics = metrics.New(a.sessions.Count, db.CheckHealth)
+		go func() {
+			if err := a.metrics.Serve(ctx, cfg.Metrics.Addr); err != nil {
+				log.Error("Metrics server failed", "err", err)
+			}
+		}()
+	}
+
+	a.resume = content.NewStore(func() (string, error) {
+		return pages.LoadResumeMarkdown(db, cfg.Content.ResumePath)
+	}, a.theme.Glamour)
+	if err := a.resume.Reload(); err != nil {
+		log.Error("Failed to load resume", "err", err)
+	}
+	a.docs = content.NewLibrary(cfg.Content.DocsDir, a.theme.Glamour)
+	if err := a.docs.Reload(); err != nil {
+		log.Error("Failed to load documents", "err", err)
+	}
+	// The watchers rebuild the search index after every reload.
+	a.index = content.NewIndex()
+	a.index.Rebuild(a.resume, a.docs)
+
+	// The resume comes from the database once one is imported and from
+	// resume.md until then, so changes to either reload it.
+	reloadResume := func() {
+		if err := a.resume.Reload(); err != nil {
+			log.Error("Failed to reload resume", "err", err)
+		} else {
+			log.Info("Reloaded resume")
+		}
+		a.index.Rebuild(a.resume, a.docs)
+	}
+	go content.Watch(ctx, cfg.Content.PollInterval, []string{cfg.Content.ResumePath}, reloadResume)
+	go content.Poll(ctx, cfg.Content.PollInterval, func() (int64, error) {
+		v, err := db.ResumeVersion(ctx)
+		if err != nil {
+			log.Error("Failed to check the resume for changes", "err", err)
+		}
+		return v, err
+	}, reloadResume)
+	go content.Watch(ctx, cfg.Content.PollInterval, []string{cfg.Content.DocsDir}, func() {
+		if err := a.docs.Reload(); err != nil {
+			log.Error("Failed to reload documents", "err", err)
+		}
+		a.index.Rebuild(a.resume, a.docs)
+	})
+
+	// Without a configured mailer the queue would only ever fail, so the
+	// contact page falls back to reporting the configuration error instead.
+	if mailer != nil {
+		opts := mail.DefaultQueueOptions()
+		opts.MaxAttempts = cfg.Mail.MaxAttempts
+		opts.OnDelivery = a.metrics.Delivery
+		q := mail.NewQueue(db, mailer, opts)
+		go q.Run(ctx)
+		a.queue = q
+	}
+
+	if cfg.Health.Addr != "" {
+		h := a.newHealth()
+		go func() {
+			if err := h.Serve(ctx, cfg.Health.Addr); err != nil {
+				log.Error("Health server failed", "err", err)
+			}
+		}()
+	}
+
+	limiter := server.NewRateLimiter(cfg.RateLimit.ConnectionsPerMinute, cfg.RateLimit.SessionsPerIP, cfg.RateLimit.SessionsPerKey)
+	server.InitServer(cfg.Server, db, db, a.sessions, limiter, a.metrics.Middleware(), a.teaHandler)
 }
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"DragonTUI/internal/db"
+
+	"gopkg.in/yaml.v3"
+)
+
+const resumeUsage = `usage: app resume <command>
+
+commands:
+  import <file.yaml>  replace the resume in the database with the file's,
+                      see resume.example.yaml
+  clear               delete the resume from the database, so the About
+                      page shows content.resume_path again`
+
+// resumeFile is the YAML layout app resume import reads. Entries are shown
+// in the order they are listed.
+type resumeFile struct {
+	Profile    *resumeProfile    `yaml:"profile"`
+	Links      []resumeLink      `yaml:"links"`
+	Skills     []resumeSkills    `yaml:"skills"`
+	Experience []resumeJob       `yaml:"experience"`
+	Projects   []resumeProject   `yaml:"projects"`
+	Education  []resumeEducation `yaml:"education"`
+}
+
+type resumeProfile struct {
+	Name     string `yaml:"name"`
+	Headline string `yaml:"headline"`
+	Email    string `yaml:"email"`
+	Location string `yaml:"location"`
+	Summary  string `yaml:"summary"`
+}
+
+type resumeLink struct {
+	Label string `yaml:"label"`
+	URL   string `yaml:"url"`
+}
+
+type resumeSkills struct {
+	Category string   `yaml:"category"`
+	Names    []string `yaml:"names"`
+}
+
+type resumeJob struct {
+	Company     string `yaml:"company"`
+	Role        string `yaml:"role"`
+	Location    string `yaml:"location"`
+	Start       string `yaml:"start"`
+	End         string `yaml:"end"`
+	Description string `yaml:"description"`
+}
+
+type resumeProject struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	URL         string `yaml:"url"`
+	Tech        string `yaml:"tech"`
+}
+
+type resumeEducation struct {
+	Institution string `yaml:"institution"`
+	Degree      string `yaml:"degree"`
+	Start       string `yaml:"start"`
+	End         string `yaml:"end"`
+	Description string `yaml:"description"`
+}
+
+// readResumeFile parses the resume at path, refusing unknown fields and
+// entries missing what the database requires.
+func readResumeFile(path string) (*db.Resume, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rf resumeFile
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&rf); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	var (
+		r    db.Resume
+		errs []error
+	)
+	missing := func(entry string, i int, field string) {
+		errs = append(errs, fmt.Errorf("%s[%d]: %s is required", entry, i, field))
+	}
+	if p := rf.Profile; p != nil {
+		if p.Name == "" {
+			errs = append(errs, errors.New("profile: name is required"))
+		}
+		r.Profile = &db.Profile{Name: p.Name, Headline: p.Headline, Email: p.Email, Location: p.Location, Summary: p.Summary}
+	}
+	for i, l := range rf.Links {
+		if l.Label == "" {
+			missing("links", i, "label")
+		}
+		if l.URL == "" {
+			missing("links", i, "url")
+		}
+		r.Links = append(r.Links, db.Link{Label: l.Label, URL: l.URL, Position: i})
+	}
+	for i, s := range rf.Skills {
+		for _, name := range s.Names {
+			if name == "" {
+				missing("skills", i, "every name")
+				continue
+			}
+			r.Skills = append(r.Skills, db.Skill{Category: s.Category, Name: name, Position: len(r.Skills)})
+		}
+	}
+	for i, e := range rf.Experience {
+		if e.Company == "" {
+			missing("experience", i, "company")
+		}
+		if e.Role == "" {
+			missing("experience", i, "role")
+		}
+		r.Experience = append(r.Experience, db.Experience{
+			Company: e.Company, Role: e.Role, Location: e.Location,
+			StartDate: e.Start, EndDate: e.End, Description: e.Description, Position: i,
+		})
+	}
+	for i, p := range rf.Projects {
+		if p.Name == "" {
+			missing("projects", i, "name")
+		}
+		r.Projects = append(r.Projects, db.Project{Name: p.Name, Description: p.Description, URL: p.URL, Tech: p.Tech, Position: i})
+	}
+	for i, e := range rf.Education {
+		if e.Institution == "" {
+			missing("education", i, "institution")
+		}
+		r.Education = append(r.Education, db.Education{
+			Institution: e.Institution, Degree: e.Degree,
+			StartDate: e.Start, EndDate: e.End, Description: e.Description, Position: i,
+		})
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid resume %s:\n%w", path, err)
+	}
+	return &r, nil
+}
+
+func runResume(dbPath string, args []string) error {
+	if len(args) == 0 {
+		return errors.New(resumeUsage)
+	}
+
+	var r *db.Resume
+	switch args[0] {
+	case "import":
+		if len(args) != 2 {
+			return fmt.Errorf("import takes a file\n%s", resumeUsage)
+		}
+		var err error
+		if r, err = readResumeFile(args[1]); err != nil {
+			return err
+		}
+	case "clear":
+		r = &db.Resume{}
+	default:
+		return fmt.Errorf("unknown resume command %q\n%s", args[0], resumeUsage)
+	}
+
+	database, err := db.InitDatabase(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := database.ImportResume(ctx, r); err != nil {
+		return err
+	}
+	if r.IsEmpty() {
+		fmt.Println("cleared the resume")
+		return nil
+	}
+	fmt.Printf("imported %d skills, %d jobs, %d projects, %d schools and %d links\n",
+		len(r.Skills), len(r.Experience), len(r.Projects), len(r.Education), len(r.Links))
+	return nil
+}
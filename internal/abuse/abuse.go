@@ -0,0 +1,87 @@
+// Package abuse screens contact form submissions for spam before they are
+// emailed. A Pipeline runs a list of Filters; each can let a submission
+// through, quarantine it for an admin to review, or reject it outright.
+package abuse
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// Submission is a contact message together with who sent it.
+type Submission struct {
+	Name           string
+	Email          string
+	Body           string
+	KeyFingerprint string
+	IP             string
+}
+
+type Action int
+
+const (
+	Allow Action = iota
+	// Quarantine stores the message without emailing it.
+	Quarantine
+	// Reject drops the message and tells the sender.
+	Reject
+)
+
+func (a Action) String() string {
+	switch a {
+	case Quarantine:
+		return "quarantine"
+	case Reject:
+		return "reject"
+	default:
+		return "allow"
+	}
+}
+
+// Verdict is what a filter decided, and why.
+type Verdict struct {
+	Action Action
+	Reason string
+}
+
+// Filter judges a submission.
+type Filter interface {
+	Check(ctx context.Context, s Submission) (Verdict, error)
+}
+
+// FilterFunc adapts a function to a Filter.
+type FilterFunc func(ctx context.Context, s Submission) (Verdict, error)
+
+func (f FilterFunc) Check(ctx context.Context, s Submission) (Verdict, error) {
+	return f(ctx, s)
+}
+
+// Pipeline runs every filter and returns the harshest verdict. It stops at
+// the first rejection. A filter that fails is logged and skipped, so a
+// broken check never costs a genuine visitor their message.
+type Pipeline []Filter
+
+func (p Pipeline) Check(ctx context.Context, s Submission) (Verdict, error) {
+	var (
+		verdict Verdict
+		reasons []string
+	)
+	for _, f := range p {
+		v, err := f.Check(ctx, s)
+		if err != nil {
+			log.Error("Abuse filter failed", "err", err)
+			continue
+		}
+		switch v.Action {
+		case Reject:
+			return v, nil
+		case Quarantine:
+			verdict.Action = Quarantine
+			reasons = append(reasons, v.Reason)
+		}
+	}
+	verdict.Reason = strings.Join(reasons, "; ")
+	return verdict, nil
+}
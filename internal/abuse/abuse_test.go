@@ -0,0 +1,35 @@
+package abuse
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	verdict := func(a Action, reason string) Filter {
+		return FilterFunc(func(context.Context, Submission) (Verdict, error) { return Verdict{a, reason}, nil })
+	}
+	broken := FilterFunc(func(context.Context, Submission) (Verdict, error) {
+		return Verdict{Action: Reject}, errors.New("database is locked")
+	})
+	tests := []struct {
+		name     string
+		pipeline Pipeline
+		want     Verdict
+	}{
+		{"empty", nil, Verdict{}},
+		{"all allow", Pipeline{verdict(Allow, ""), verdict(Allow, "")}, Verdict{}},
+		{"quarantines add up", Pipeline{verdict(Quarantine, "links"), verdict(Allow, ""), verdict(Quarantine, "keyword")}, Verdict{Quarantine, "links; keyword"}},
+		{"reject wins", Pipeline{verdict(Quarantine, "links"), verdict(Reject, "blocked"), verdict(Quarantine, "keyword")}, Verdict{Reject, "blocked"}},
+		{"broken filters are skipped", Pipeline{broken, verdict(Quarantine, "links")}, Verdict{Quarantine, "links"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pipeline.Check(context.Background(), Submission{})
+			if err != nil || got != tt.want {
+				t.Errorf("Check() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package abuse
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Challenge is a small sum that visitors without an SSH key solve before
+// their message is accepted. It won't stop a determined human, but it stops
+// scripts that blindly fill in the form.
+type Challenge struct {
+	Question string
+	answer   int
+}
+
+var numberWords = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+func NewChallenge() *Challenge {
+	a, b := rand.IntN(10)+1, rand.IntN(10)
+	return &Challenge{
+		Question: fmt.Sprintf("What is %s plus %d?", numberWords[a], b),
+		answer:   a + b,
+	}
+}
+
+// Check reports whether answer solves the challenge.
+func (c *Challenge) Check(answer string) bool {
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	return err == nil && n == c.answer
+}
@@ -0,0 +1,92 @@
+package abuse
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"DragonTUI/internal/db"
+)
+
+// BlocklistStore is the blocklist table.
+type BlocklistStore interface {
+	IsBlocked(ctx context.Context, kind db.BlockKind, value string) (bool, error)
+}
+
+// Blocklist rejects submissions from a blocked email address, key or IP.
+func Blocklist(store BlocklistStore) Filter {
+	return FilterFunc(func(ctx context.Context, s Submission) (Verdict, error) {
+		checks := []struct {
+			kind  db.BlockKind
+			value string
+		}{
+			{db.BlockEmail, s.Email},
+			{db.BlockKey, s.KeyFingerprint},
+			{db.BlockIP, s.IP},
+		}
+		for _, c := range checks {
+			if c.value == "" {
+				continue
+			}
+			blocked, err := store.IsBlocked(ctx, c.kind, c.value)
+			if err != nil {
+				return Verdict{}, err
+			}
+			if blocked {
+				// Blocked senders aren't told which of their details matched.
+				return Verdict{Action: Reject, Reason: "this sender is blocked"}, nil
+			}
+		}
+		return Verdict{}, nil
+	})
+}
+
+// DuplicateStore finds messages that were already sent.
+type DuplicateStore interface {
+	HasRecentDuplicate(ctx context.Context, body, email, fingerprint string, since time.Time) (bool, error)
+}
+
+// Duplicates rejects a message the same sender already sent within window.
+func Duplicates(store DuplicateStore, window time.Duration) Filter {
+	return FilterFunc(func(ctx context.Context, s Submission) (Verdict, error) {
+		dup, err := store.HasRecentDuplicate(ctx, s.Body, s.Email, s.KeyFingerprint, time.Now().Add(-window))
+		if err != nil {
+			return Verdict{}, err
+		}
+		if dup {
+			return Verdict{Action: Reject, Reason: "you already sent this message"}, nil
+		}
+		return Verdict{}, nil
+	})
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.|\[url=`)
+
+// Heuristics quarantines messages with more than maxLinks links or any of
+// keywords, matched case-insensitively.
+func Heuristics(maxLinks int, keywords []string) Filter {
+	lower := make([]string, 0, len(keywords))
+	for _, k := range keywords {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			lower = append(lower, k)
+		}
+	}
+	return FilterFunc(func(ctx context.Context, s Submission) (Verdict, error) {
+		var reasons []string
+		if links := len(linkPattern.FindAllStringIndex(s.Body, -1)); links > maxLinks {
+			reasons = append(reasons, fmt.Sprintf("%d links", links))
+		}
+		text := strings.ToLower(s.Name + " " + s.Body)
+		for _, k := range lower {
+			if strings.Contains(text, k) {
+				reasons = append(reasons, fmt.Sprintf("keyword %q", k))
+			}
+		}
+		if len(reasons) == 0 {
+			return Verdict{}, nil
+		}
+		return Verdict{Action: Quarantine, Reason: strings.Join(reasons, ", ")}, nil
+	})
+}
@@ -0,0 +1,121 @@
+package abuse
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"DragonTUI/internal/db"
+)
+
+func TestHeuristics(t *testing.T) {
+	filter := Heuristics(2, []string{"Casino", " crypto ", ""})
+	tests := []struct {
+		name string
+		sub  Submission
+		want Verdict
+	}{
+		{"plain", Submission{Name: "Ann", Body: "Loved the talk."}, Verdict{}},
+		{"links at the limit", Submission{Body: "See https://a.example and www.b.example"}, Verdict{}},
+		{
+			name: "too many links",
+			sub:  Submission{Body: "http://a.example HTTPS://b.example [url=c]c[/url]"},
+			want: Verdict{Action: Quarantine, Reason: "3 links"},
+		},
+		{"link-like words", Submission{Body: "httpsmith www_example ahttp://"}, Verdict{}},
+		{
+			name: "keyword in any case",
+			sub:  Submission{Body: "Best CASINO in town"},
+			want: Verdict{Action: Quarantine, Reason: `keyword "casino"`},
+		},
+		{
+			name: "keyword in the name",
+			sub:  Submission{Name: "Crypto King", Body: "Hi"},
+			want: Verdict{Action: Quarantine, Reason: `keyword "crypto"`},
+		},
+		{
+			name: "every reason",
+			sub:  Submission{Body: "casino crypto http://a http://b http://c"},
+			want: Verdict{Action: Quarantine, Reason: `3 links, keyword "casino", keyword "crypto"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Check(context.Background(), tt.sub)
+			if err != nil || got != tt.want {
+				t.Errorf("Check() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.InitDatabase(filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	sent := []db.Message{
+		{Email: "ann@example.com", KeyFingerprint: "SHA256:ann", Body: "Hello"},
+		{Email: "old@example.com", Body: "Hello", CreatedAt: time.Now().Add(-2 * time.Hour)},
+	}
+	for i := range sent {
+		if err := store.CreateMessage(ctx, &sent[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter := Duplicates(store, time.Hour)
+	rejected := Verdict{Action: Reject, Reason: "you already sent this message"}
+	tests := []struct {
+		name string
+		sub  Submission
+		want Verdict
+	}{
+		{"same email", Submission{Email: "ann@example.com", Body: "Hello"}, rejected},
+		{"same key", Submission{Email: "other@example.com", KeyFingerprint: "SHA256:ann", Body: "Hello"}, rejected},
+		{"different body", Submission{Email: "ann@example.com", Body: "Hello again"}, Verdict{}},
+		{"different sender", Submission{Email: "bob@example.com", KeyFingerprint: "SHA256:bob", Body: "Hello"}, Verdict{}},
+		{"no key never matches by key", Submission{Email: "bob@example.com", Body: "Hello"}, Verdict{}},
+		{"outside the window", Submission{Email: "old@example.com", Body: "Hello"}, Verdict{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Check(ctx, tt.sub)
+			if err != nil || got != tt.want {
+				t.Errorf("Check() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+type fakeBlocklist map[db.BlockKind]string
+
+func (b fakeBlocklist) IsBlocked(ctx context.Context, kind db.BlockKind, value string) (bool, error) {
+	return b[kind] == value, nil
+}
+
+func TestBlocklist(t *testing.T) {
+	filter := Blocklist(fakeBlocklist{db.BlockEmail: "spam@example.com", db.BlockKey: "SHA256:spam", db.BlockIP: "10.0.0.66"})
+	rejected := Verdict{Action: Reject, Reason: "this sender is blocked"}
+	tests := []struct {
+		name string
+		sub  Submission
+		want Verdict
+	}{
+		{"clean", Submission{Email: "ann@example.com", KeyFingerprint: "SHA256:ann", IP: "10.0.0.1"}, Verdict{}},
+		{"email", Submission{Email: "spam@example.com"}, rejected},
+		{"key", Submission{Email: "ann@example.com", KeyFingerprint: "SHA256:spam"}, rejected},
+		{"ip", Submission{Email: "ann@example.com", IP: "10.0.0.66"}, rejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filter.Check(context.Background(), tt.sub)
+			if err != nil || got != tt.want {
+				t.Errorf("Check() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
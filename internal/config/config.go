@@ -0,0 +1,529 @@
+// Package config loads DragonTUI's settings from a YAML file, environment
+// variables and command-line flags. Later sources win: flags override the
+// environment, which overrides the file, which overrides the defaults.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Mail      MailConfig      `yaml:"mail"`
+	Content   ContentConfig   `yaml:"content"`
+	Theme     ThemeConfig     `yaml:"theme"`
+	Projects  ProjectsConfig  `yaml:"projects"`
+	Admin     AdminConfig     `yaml:"admin"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Abuse     AbuseConfig     `yaml:"abuse"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Health    HealthConfig    `yaml:"health"`
+	Log       LogConfig       `yaml:"log"`
+}
+
+type ServerConfig struct {
+	Host        string `yaml:"host"`
+	Port        int    `yaml:"port"`
+	HostKeyPath string `yaml:"host_key_path"`
+	// AnonymousAccess lets visitors without an SSH key in through
+	// keyboard-interactive authentication.
+	AnonymousAccess bool `yaml:"anonymous_access"`
+	// ShutdownGrace is how long visitors are warned before their sessions
+	// are closed when the server stops.
+	ShutdownGrace time.Duration `yaml:"shutdown_grace"`
+}
+
+type DatabaseConfig struct {
+	Path string `yaml:"path"`
+}
+
+const (
+	MailResend   = "resend"
+	MailSMTP     = "smtp"
+	MailMaildir  = "maildir"
+	MailDisabled = "disabled"
+)
+
+type MailConfig struct {
+	Backend      string     `yaml:"backend"`
+	From         string     `yaml:"from"`
+	To           []string   `yaml:"to"`
+	ResendAPIKey string     `yaml:"resend_api_key"`
+	SMTP         SMTPConfig `yaml:"smtp"`
+	MaildirPath  string     `yaml:"maildir_path"`
+	MaxAttempts  int        `yaml:"max_attempts"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	StartTLS bool   `yaml:"starttls"`
+}
+
+type ContentConfig struct {
+	ResumePath string `yaml:"resume_path"`
+	// DocsDir holds the markdown documents listed on the Writing page.
+	DocsDir string `yaml:"docs_dir"`
+	// PollInterval is how often content files are checked for changes.
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+const (
+	ProjectsManifest  = "manifest"
+	ProjectsSoftServe = "softserve"
+	ProjectsDisabled  = "disabled"
+)
+
+type ProjectsConfig struct {
+	// Source is where the Projects page gets its repositories from.
+	Source string `yaml:"source"`
+	// Manifest is a JSON or YAML file listing projects, used by the
+	// manifest source.
+	Manifest  string          `yaml:"manifest"`
+	SoftServe SoftServeConfig `yaml:"softserve"`
+}
+
+type SoftServeConfig struct {
+	// Addr is soft-serve's SSH listener, 23231 in docker-compose.yml.
+	Addr string `yaml:"addr"`
+	User string `yaml:"user"`
+	// KeyPath is the private key DragonTUI signs in with. It defaults to
+	// the server's own host key.
+	KeyPath string `yaml:"key_path"`
+	// KnownHosts verifies soft-serve's host key. Without it the server
+	// won't start unless InsecureSkipHostKey is set, which only makes sense
+	// for a soft-serve on the same host.
+	KnownHosts          string `yaml:"known_hosts"`
+	InsecureSkipHostKey bool   `yaml:"insecure_skip_host_key"`
+}
+
+type AdminConfig struct {
+	// Fingerprints are SHA256 public key fingerprints, as printed by
+	// ssh-keygen -lf, that unlock the admin pages.
+	Fingerprints []string `yaml:"fingerprints"`
+	// AuthorizedFile lists more admins, as fingerprints or authorized_keys
+	// lines. Admins can also be added with `app admin add`.
+	AuthorizedFile string `yaml:"authorized_file"`
+}
+
+// RateLimitConfig keeps one visitor from hogging the server or the mail
+// quota. Zero turns a limit off.
+type RateLimitConfig struct {
+	// ConnectionsPerMinute is how many sessions one address may open per
+	// minute.
+	ConnectionsPerMinute int `yaml:"connections_per_minute"`
+	// SessionsPerIP and SessionsPerKey cap the sessions open at once from
+	// one address and with one public key.
+	SessionsPerIP  int `yaml:"sessions_per_ip"`
+	SessionsPerKey int `yaml:"sessions_per_key"`
+	// Submissions is how many contact messages one address or key may send
+	// per SubmissionWindow. The counts are kept in the database.
+	Submissions      int           `yaml:"submissions"`
+	SubmissionWindow time.Duration `yaml:"submission_window"`
+}
+
+// AbuseConfig tunes the spam checks on contact messages. Blocked senders are
+// managed with `app blocklist`.
+type AbuseConfig struct {
+	// MaxLinks is how many links a message can have before it is
+	// quarantined.
+	MaxLinks int `yaml:"max_links"`
+	// Keywords quarantine any message that contains one of them.
+	Keywords []string `yaml:"keywords"`
+	// DuplicateWindow is how long a sender can't send the same message
+	// again.
+	DuplicateWindow time.Duration `yaml:"duplicate_window"`
+	// Challenge asks visitors without an SSH key to solve a sum.
+	Challenge bool `yaml:"challenge"`
+}
+
+type ThemeConfig struct {
+	// Default is the theme sessions start in, and Light the one they start
+	// in on terminals with a light background.
+	Default string `yaml:"default"`
+	Light   string `yaml:"light"`
+	// Dir holds extra themes as JSON files, one per theme. Empty offers
+	// only the built-in ones.
+	Dir string `yaml:"dir"`
+}
+
+type MetricsConfig struct {
+	// Addr is where Prometheus metrics are served over HTTP, at /metrics.
+	// Empty turns metrics off.
+	Addr string `yaml:"addr"`
+}
+
+type HealthConfig struct {
+	// Addr is where /healthz and /readyz are served over HTTP. Empty turns
+	// the probes off.
+	Addr string `yaml:"addr"`
+}
+
+const (
+	LogText   = "text"
+	LogJSON   = "json"
+	LogLogfmt = "logfmt"
+)
+
+type LogConfig struct {
+	// File is where the server logs; empty logs to stderr.
+	File string `yaml:"file"`
+	// Level is the least severe level written: debug, info, warn or error.
+	Level string `yaml:"level"`
+	// Format is text, json or logfmt.
+	Format string `yaml:"format"`
+}
+
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Host:            "localhost",
+			Port:            5173,
+			HostKeyPath:     ".ssh/term_info_ed25519",
+			AnonymousAccess: true,
+			ShutdownGrace:   10 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Path: "dragon.db",
+		},
+		Mail: MailConfig{
+			Backend:     MailResend,
+			From:        "DragonTUI <noreply@resend.dev>",
+			To:          []string{"ebmpinyuri@gmail.com"},
+			SMTP:        SMTPConfig{Port: 587},
+			MaxAttempts: 8,
+		},
+		Content: ContentConfig{
+			ResumePath:   "resume.md",
+			DocsDir:      "docs",
+			PollInterval: 2 * time.Second,
+		},
+		Theme: ThemeConfig{
+			Default: "dragon",
+			Light:   "paper",
+		},
+		Projects: ProjectsConfig{
+			Source:   ProjectsManifest,
+			Manifest: "projects.yaml",
+			SoftServe: SoftServeConfig{
+				Addr: "localhost:23231",
+				User: "dragontui",
+			},
+		},
+		Admin: AdminConfig{
+			AuthorizedFile: "authorized_admins",
+		},
+		RateLimit: RateLimitConfig{
+			ConnectionsPerMinute: 10,
+			SessionsPerIP:        5,
+			SessionsPerKey:       3,
+			Submissions:          3,
+			SubmissionWindow:     time.Hour,
+		},
+		Abuse: AbuseConfig{
+			MaxLinks:        2,
+			Keywords:        []string{"casino", "viagra", "seo services", "backlinks", "crypto investment"},
+			DuplicateWindow: 24 * time.Hour,
+			Challenge:       true,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: LogText,
+		},
+	}
+}
+
+// Load builds the configuration for the program called name from args. It
+// returns the arguments left after flag parsing. Every malformed value is
+// reported in the returned error, not just the first one.
+func Load(name string, args []string) (*Config, []string, error) {
+	cfg := Default()
+
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath := set.String("config", "", "path to a YAML config file (env CONFIG_FILE)")
+	envFile := set.String("env-file", ".env", "dotenv file to load into the environment if present")
+	flags := cfg.flags(set)
+	if err := set.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if err := godotenv.Load(*envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("loading %s: %w", *envFile, err)
+	}
+
+	path := *configPath
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var errs []error
+	errs = append(errs, cfg.loadEnv()...)
+
+	// Flags are applied last, and only those given explicitly, so that
+	// their defaults don't mask the file or environment.
+	set.Visit(func(f *flag.Flag) {
+		if apply, ok := flags[f.Name]; ok {
+			if err := apply(f.Value.String()); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", f.Name, err))
+			}
+		}
+	})
+
+	return cfg, set.Args(), errors.Join(errs...)
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+type setter func(string) error
+
+func setString(dst *string) setter {
+	return func(v string) error {
+		*dst = v
+		return nil
+	}
+}
+
+func setInt(dst *int) setter {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", v)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func setBool(dst *bool) setter {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+		*dst = b
+		return nil
+	}
+}
+
+func setDuration(dst *time.Duration) setter {
+	return func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", v)
+		}
+		*dst = d
+		return nil
+	}
+}
+
+func setList(dst *[]string) setter {
+	return func(v string) error {
+		*dst = splitList(v)
+		return nil
+	}
+}
+
+func (c *Config) envSetters() map[string]setter {
+	return map[string]setter{
+		"APP_HOST":                          setString(&c.Server.Host),
+		"APP_PORT":                          setInt(&c.Server.Port),
+		"HOST_KEY_PATH":                     setString(&c.Server.HostKeyPath),
+		"SHUTDOWN_GRACE":                    setDuration(&c.Server.ShutdownGrace),
+		"SSH_ANONYMOUS_ACCESS":              setBool(&c.Server.AnonymousAccess),
+		"DB_URL":                            setString(&c.Database.Path),
+		"LOG_FILE":                          setString(&c.Log.File),
+		"LOG_LEVEL":                         setString(&c.Log.Level),
+		"LOG_FORMAT":                        setString(&c.Log.Format),
+		"RESUME_PATH":                       setString(&c.Content.ResumePath),
+		"DOCS_DIR":                          setString(&c.Content.DocsDir),
+		"CONTENT_POLL_INTERVAL":             setDuration(&c.Content.PollInterval),
+		"PROJECTS_SOURCE":                   setString(&c.Projects.Source),
+		"PROJECTS_MANIFEST":                 setString(&c.Projects.Manifest),
+		"SOFT_SERVE_ADDR":                   setString(&c.Projects.SoftServe.Addr),
+		"SOFT_SERVE_USER":                   setString(&c.Projects.SoftServe.User),
+		"SOFT_SERVE_KEY_PATH":               setString(&c.Projects.SoftServe.KeyPath),
+		"SOFT_SERVE_KNOWN_HOSTS":            setString(&c.Projects.SoftServe.KnownHosts),
+		"SOFT_SERVE_INSECURE_SKIP_HOST_KEY": setBool(&c.Projects.SoftServe.InsecureSkipHostKey),
+		"ADMIN_FINGERPRINTS":                setList(&c.Admin.Fingerprints),
+		"ADMIN_AUTHORIZED_FILE":             setString(&c.Admin.AuthorizedFile),
+		"RATE_LIMIT_CONNECTIONS_PER_MINUTE": setInt(&c.RateLimit.ConnectionsPerMinute),
+		"RATE_LIMIT_SESSIONS_PER_IP":        setInt(&c.RateLimit.SessionsPerIP),
+		"RATE_LIMIT_SESSIONS_PER_KEY":       setInt(&c.RateLimit.SessionsPerKey),
+		"RATE_LIMIT_SUBMISSIONS":            setInt(&c.RateLimit.Submissions),
+		"RATE_LIMIT_SUBMISSION_WINDOW":      setDuration(&c.RateLimit.SubmissionWindow),
+		"ABUSE_MAX_LINKS":                   setInt(&c.Abuse.MaxLinks),
+		"ABUSE_KEYWORDS":                    setList(&c.Abuse.Keywords),
+		"ABUSE_DUPLICATE_WINDOW":            setDuration(&c.Abuse.DuplicateWindow),
+		"ABUSE_CHALLENGE":                   setBool(&c.Abuse.Challenge),
+		"THEME":                             setString(&c.Theme.Default),
+		"THEME_DIR":                         setString(&c.Theme.Dir),
+		"THEME_LIGHT":                       setString(&c.Theme.Light),
+		"METRICS_ADDR":                      setString(&c.Metrics.Addr),
+		"HEALTH_ADDR":                       setString(&c.Health.Addr),
+		"MAIL_BACKEND":                      setString(&c.Mail.Backend),
+		"MAIL_FROM":                         setString(&c.Mail.From),
+		"MAIL_TO":                           setList(&c.Mail.To),
+		"MAIL_MAX_ATTEMPTS":                 setInt(&c.Mail.MaxAttempts),
+		"RESEND_API_KEY":                    setString(&c.Mail.ResendAPIKey),
+		"SMTP_HOST":                         setString(&c.Mail.SMTP.Host),
+		"SMTP_PORT":                         setInt(&c.Mail.SMTP.Port),
+		"SMTP_USERNAME":                     setString(&c.Mail.SMTP.Username),
+		"SMTP_PASSWORD":                     setString(&c.Mail.SMTP.Password),
+		"SMTP_STARTTLS":                     setBool(&c.Mail.SMTP.StartTLS),
+		"MAILDIR_PATH":                      setString(&c.Mail.MaildirPath),
+	}
+}
+
+func (c *Config) loadEnv() []error {
+	var errs []error
+	setters := c.envSetters()
+	for _, name := range slices.Sorted(maps.Keys(setters)) {
+		set := setters[name]
+		v, ok := os.LookupEnv(name)
+		if !ok || v == "" {
+			continue
+		}
+		if err := set(v); err != nil {
+			errs = append(errs, fmt.Errorf("env %s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// flags registers the command-line flags on fs and returns how to apply each
+// of them to c.
+func (c *Config) flags(fs *flag.FlagSet) map[string]setter {
+	fs.String("host", c.Server.Host, "address to listen on (env APP_HOST)")
+	fs.Int("port", c.Server.Port, "port to listen on (env APP_PORT)")
+	fs.String("host-key", c.Server.HostKeyPath, "SSH host key path (env HOST_KEY_PATH)")
+	fs.String("db", c.Database.Path, "SQLite database path (env DB_URL)")
+	fs.String("log-file", c.Log.File, "log file path, empty for stderr (env LOG_FILE)")
+	fs.String("log-format", c.Log.Format, "text, json or logfmt (env LOG_FORMAT)")
+	fs.String("resume", c.Content.ResumePath, "markdown resume shown when the database has none (env RESUME_PATH)")
+	fs.String("docs", c.Content.DocsDir, "directory of markdown documents for the Writing page (env DOCS_DIR)")
+	fs.String("theme", c.Theme.Default, "theme sessions start in (env THEME)")
+	fs.String("mail-backend", c.Mail.Backend, "resend, smtp, maildir or disabled (env MAIL_BACKEND)")
+
+	return map[string]setter{
+		"host":         setString(&c.Server.Host),
+		"port":         setInt(&c.Server.Port),
+		"host-key":     setString(&c.Server.HostKeyPath),
+		"db":           setString(&c.Database.Path),
+		"log-file":     setString(&c.Log.File),
+		"log-format":   setString(&c.Log.Format),
+		"resume":       setString(&c.Content.ResumePath),
+		"docs":         setString(&c.Content.DocsDir),
+		"theme":        setString(&c.Theme.Default),
+		"mail-backend": setString(&c.Mail.Backend),
+	}
+}
+
+// Validate reports every missing or inconsistent setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	require := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	require(c.Server.Port > 0 && c.Server.Port < 65536, "server.port %d is out of range", c.Server.Port)
+	require(c.Server.HostKeyPath != "", "server.host_key_path is required")
+	require(c.Server.ShutdownGrace >= 0, "server.shutdown_grace can't be negative")
+	require(c.Database.Path != "", "database.path (DB_URL) is required")
+	require(c.Content.PollInterval > 0, "content.poll_interval must be positive")
+	require(c.Theme.Default != "", "theme.default (THEME) is required")
+	require(c.Theme.Light != "", "theme.light (THEME_LIGHT) is required")
+	require(c.RateLimit.ConnectionsPerMinute >= 0 && c.RateLimit.SessionsPerIP >= 0 && c.RateLimit.SessionsPerKey >= 0 && c.RateLimit.Submissions >= 0,
+		"rate_limit values can't be negative")
+	require(c.Abuse.MaxLinks >= 0, "abuse.max_links can't be negative")
+	require(c.RateLimit.Submissions == 0 || c.RateLimit.SubmissionWindow > 0, "rate_limit.submission_window must be positive")
+	require(c.Health.Addr == "" || c.Health.Addr != c.Metrics.Addr, "health.addr and metrics.addr must differ")
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level %q must be debug, info, warn or error", c.Log.Level))
+	}
+	switch c.Log.Format {
+	case LogText, LogJSON, LogLogfmt:
+	default:
+		errs = append(errs, fmt.Errorf("log.format %q must be text, json or logfmt", c.Log.Format))
+	}
+
+	switch c.Projects.Source {
+	case ProjectsDisabled:
+	case ProjectsManifest:
+		require(c.Projects.Manifest != "", "projects.manifest (PROJECTS_MANIFEST) is required for the manifest source")
+	case ProjectsSoftServe:
+		require(c.Projects.SoftServe.Addr != "", "projects.softserve.addr (SOFT_SERVE_ADDR) is required for the softserve source")
+		require(c.Projects.SoftServe.KnownHosts != "" || c.Projects.SoftServe.InsecureSkipHostKey,
+			"projects.softserve.known_hosts (SOFT_SERVE_KNOWN_HOSTS) is required for the softserve source, unless insecure_skip_host_key is set")
+	default:
+		errs = append(errs, fmt.Errorf("projects.source %q must be manifest, softserve or disabled", c.Projects.Source))
+	}
+
+	switch c.Mail.Backend {
+	case MailDisabled:
+	case MailResend:
+		require(c.Mail.ResendAPIKey != "", "mail.resend_api_key (RESEND_API_KEY) is required for the resend backend")
+	case MailSMTP:
+		require(c.Mail.SMTP.Host != "", "mail.smtp.host (SMTP_HOST) is required for the smtp backend")
+		require(c.Mail.SMTP.Port > 0 && c.Mail.SMTP.Port < 65536, "mail.smtp.port %d is out of range", c.Mail.SMTP.Port)
+	case MailMaildir:
+		require(c.Mail.MaildirPath != "", "mail.maildir_path (MAILDIR_PATH) is required for the maildir backend")
+	default:
+		errs = append(errs, fmt.Errorf("mail.backend %q must be one of resend, smtp, maildir or disabled", c.Mail.Backend))
+	}
+	if c.Mail.Backend != MailDisabled {
+		require(c.Mail.From != "", "mail.from (MAIL_FROM) is required")
+		require(len(c.Mail.To) > 0, "mail.to (MAIL_TO) needs at least one recipient")
+		require(c.Mail.MaxAttempts > 0, "mail.max_attempts must be positive")
+	}
+
+	return errors.Join(errs...)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
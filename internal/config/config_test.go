@@ -0,0 +1,316 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolate clears every variable Load reads, so the tests don't depend on
+// the environment they run in, and returns flags that skip the .env file.
+func isolate(t *testing.T) []string {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	for name := range Default().envSetters() {
+		t.Setenv(name, "")
+	}
+	return []string{"-env-file", filepath.Join(t.TempDir(), "missing.env")}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "server:\n  port: 2000\n  shutdown_grace: 1m\ntheme:\n  default: file\n  light: file\n")
+	tests := []struct {
+		name  string
+		file  bool
+		env   map[string]string
+		flags []string
+		// want is the port, the default theme and the light theme.
+		wantPort  int
+		wantTheme string
+		wantLight string
+	}{
+		{name: "defaults", wantPort: 5173, wantTheme: "dragon", wantLight: "paper"},
+		{name: "file", file: true, wantPort: 2000, wantTheme: "file", wantLight: "file"},
+		{
+			name:     "env over file",
+			file:     true,
+			env:      map[string]string{"APP_PORT": "3000", "THEME_LIGHT": "env"},
+			wantPort: 3000, wantTheme: "file", wantLight: "env",
+		},
+		{
+			name:     "flags over env",
+			file:     true,
+			env:      map[string]string{"APP_PORT": "3000", "THEME": "env"},
+			flags:    []string{"-port", "4000"},
+			wantPort: 4000, wantTheme: "env", wantLight: "file",
+		},
+		{
+			name:     "flags over defaults",
+			flags:    []string{"-theme", "flag"},
+			wantPort: 5173, wantTheme: "flag", wantLight: "paper",
+		},
+		{
+			name:     "empty env is unset",
+			file:     true,
+			env:      map[string]string{"APP_PORT": ""},
+			wantPort: 2000, wantTheme: "file", wantLight: "file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := isolate(t)
+			if tt.file {
+				t.Setenv("CONFIG_FILE", file)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, rest, err := Load("app", append(append(args, tt.flags...), "migrate", "up"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Server.Port != tt.wantPort || cfg.Theme.Default != tt.wantTheme || cfg.Theme.Light != tt.wantLight {
+				t.Errorf("port %d, theme %q, light %q, want %d, %q, %q",
+					cfg.Server.Port, cfg.Theme.Default, cfg.Theme.Light, tt.wantPort, tt.wantTheme, tt.wantLight)
+			}
+			if !slices.Equal(rest, []string{"migrate", "up"}) {
+				t.Errorf("args left = %q", rest)
+			}
+		})
+	}
+}
+
+func TestLoadFlagPicksFile(t *testing.T) {
+	args := isolate(t)
+	t.Setenv("CONFIG_FILE", writeFile(t, "server:\n  port: 2000\n"))
+	cfg, _, err := Load("app", append(args, "-config", writeFile(t, "server:\n  port: 3000\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != 3000 {
+		t.Errorf("port %d, want the -config file's 3000", cfg.Server.Port)
+	}
+}
+
+func TestLoadEnvTypes(t *testing.T) {
+	args := isolate(t)
+	t.Setenv("SSH_ANONYMOUS_ACCESS", "false")
+	t.Setenv("SHUTDOWN_GRACE", "30s")
+	t.Setenv("MAIL_TO", " a@example.com, ,b@example.com ")
+	cfg, _, err := Load("app", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.AnonymousAccess || cfg.Server.ShutdownGrace != 30*time.Second ||
+		!slices.Equal(cfg.Mail.To, []string{"a@example.com", "b@example.com"}) {
+		t.Errorf("anonymous %v, grace %s, to %q", cfg.Server.AnonymousAccess, cfg.Server.ShutdownGrace, cfg.Mail.To)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		env   map[string]string
+		flags []string
+		want  []string
+	}{
+		{
+			name: "every malformed variable",
+			env:  map[string]string{"APP_PORT": "high", "SMTP_STARTTLS": "maybe", "SHUTDOWN_GRACE": "soon"},
+			want: []string{
+				`env APP_PORT: "high" is not a number`,
+				`env SHUTDOWN_GRACE: "soon" is not a duration`,
+				`env SMTP_STARTTLS: "maybe" is not a boolean`,
+			},
+		},
+		{name: "malformed flag", flags: []string{"-port", "x"}, want: []string{`invalid value "x" for flag -port`}},
+		{name: "unknown field", file: "server:\n  prot: 2000\n", want: []string{"field prot not found"}},
+		{name: "missing file", flags: []string{"-config", "missing.yaml"}, want: []string{"config file: open missing.yaml"}},
+		{name: "unknown flag", flags: []string{"-nope"}, want: []string{"flag provided but not defined: -nope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := isolate(t)
+			if tt.file != "" {
+				t.Setenv("CONFIG_FILE", writeFile(t, tt.file))
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, _, err := Load("app", append(args, tt.flags...))
+			if err == nil {
+				t.Fatal("Load() succeeded")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExampleConfigLoads(t *testing.T) {
+	args := isolate(t)
+	cfg, _, err := Load("app", append(args, "-config", "../../config.example.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Mail.ResendAPIKey = "re_test"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("the example config is invalid: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   []string
+	}{
+		{name: "valid", change: func(c *Config) {}},
+		{name: "mail disabled needs no recipients", change: func(c *Config) {
+			c.Mail = MailConfig{Backend: MailDisabled}
+		}},
+		{name: "smtp", change: func(c *Config) {
+			c.Mail.Backend, c.Mail.SMTP.Host = MailSMTP, "smtp.example.com"
+		}},
+		{
+			name:   "port",
+			change: func(c *Config) { c.Server.Port = 70000 },
+			want:   []string{"server.port 70000 is out of range"},
+		},
+		{
+			name:   "resend key",
+			change: func(c *Config) { c.Mail.ResendAPIKey = "" },
+			want:   []string{"mail.resend_api_key (RESEND_API_KEY) is required"},
+		},
+		{
+			name: "smtp settings",
+			change: func(c *Config) {
+				c.Mail.Backend, c.Mail.SMTP.Port = MailSMTP, 0
+			},
+			want: []string{"mail.smtp.host (SMTP_HOST) is required", "mail.smtp.port 0 is out of range"},
+		},
+		{
+			name:   "maildir path",
+			change: func(c *Config) { c.Mail.Backend = MailMaildir },
+			want:   []string{"mail.maildir_path (MAILDIR_PATH) is required"},
+		},
+		{
+			name:   "unknown mail backend",
+			change: func(c *Config) { c.Mail.Backend = "pigeon" },
+			want:   []string{`mail.backend "pigeon" must be one of`},
+		},
+		{
+			name: "mail recipients and attempts",
+			change: func(c *Config) {
+				c.Mail.To, c.Mail.MaxAttempts = nil, 0
+			},
+			want: []string{"mail.to (MAIL_TO) needs at least one recipient", "mail.max_attempts must be positive"},
+		},
+		{
+			name:   "negative limits",
+			change: func(c *Config) { c.RateLimit.SessionsPerKey, c.Abuse.MaxLinks = -1, -1 },
+			want:   []string{"rate_limit values can't be negative", "abuse.max_links can't be negative"},
+		},
+		{
+			name:   "submission window",
+			change: func(c *Config) { c.RateLimit.SubmissionWindow = 0 },
+			want:   []string{"rate_limit.submission_window must be positive"},
+		},
+		{
+			name:   "submissions off needs no window",
+			change: func(c *Config) { c.RateLimit.Submissions, c.RateLimit.SubmissionWindow = 0, 0 },
+		},
+		{
+			name:   "shared probe address",
+			change: func(c *Config) { c.Health.Addr, c.Metrics.Addr = ":9090", ":9090" },
+			want:   []string{"health.addr and metrics.addr must differ"},
+		},
+		{
+			name:   "log settings",
+			change: func(c *Config) { c.Log.Level, c.Log.Format = "loud", "xml" },
+			want:   []string{`log.level "loud"`, `log.format "xml"`},
+		},
+		{
+			name:   "projects source",
+			change: func(c *Config) { c.Projects.Source = "github" },
+			want:   []string{`projects.source "github" must be`},
+		},
+		{
+			name:   "manifest path",
+			change: func(c *Config) { c.Projects.Manifest = "" },
+			want:   []string{"projects.manifest (PROJECTS_MANIFEST) is required"},
+		},
+		{
+			name: "softserve addr",
+			change: func(c *Config) {
+				c.Projects.Source, c.Projects.SoftServe.Addr, c.Projects.SoftServe.KnownHosts = ProjectsSoftServe, "", "known_hosts"
+			},
+			want: []string{"projects.softserve.addr (SOFT_SERVE_ADDR) is required"},
+		},
+		{
+			name:   "softserve known hosts",
+			change: func(c *Config) { c.Projects.Source = ProjectsSoftServe },
+			want:   []string{"projects.softserve.known_hosts (SOFT_SERVE_KNOWN_HOSTS) is required"},
+		},
+		{
+			name: "softserve host key check skipped",
+			change: func(c *Config) {
+				c.Projects.Source, c.Projects.SoftServe.InsecureSkipHostKey = ProjectsSoftServe, true
+			},
+		},
+		{
+			name: "softserve known hosts set",
+			change: func(c *Config) {
+				c.Projects.Source, c.Projects.SoftServe.KnownHosts = ProjectsSoftServe, "known_hosts"
+			},
+		},
+		{
+			name: "every problem at once",
+			change: func(c *Config) {
+				c.Database.Path, c.Theme.Default, c.Content.PollInterval = "", "", 0
+			},
+			want: []string{"database.path (DB_URL) is required", "theme.default (THEME) is required", "content.poll_interval must be positive"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Mail.ResendAPIKey = "re_test"
+			tt.change(cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() succeeded")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+			if n := strings.Count(err.Error(), "\n") + 1; n != len(tt.want) {
+				t.Errorf("Validate() reported %d problems, want %d:\n%v", n, len(tt.want), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package content
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/muesli/termenv"
+)
+
+// codeFormatters are the highlighters for each terminal color profile. A
+// terminal without color gets the source as it is.
+var codeFormatters = map[termenv.Profile]chroma.Formatter{
+	termenv.TrueColor: formatters.TTY16m,
+	termenv.ANSI256:   formatters.TTY256,
+	termenv.ANSI:      formatters.TTY16,
+}
+
+// RenderCode syntax-highlights source for a terminal with the color profile,
+// choosing the lexer from the file name and falling back to content
+// analysis. Files that look binary are not highlighted.
+func RenderCode(name, source, style string, profile termenv.Profile) (string, error) {
+	if strings.ContainsRune(source, 0) {
+		return "(binary file)", nil
+	}
+	formatter, ok := codeFormatters[profile]
+	if !ok {
+		return source, nil
+	}
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Analyse(source)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, source)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := formatter.Format(&b, styles.Get(style), iterator); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
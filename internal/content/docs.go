@@ -0,0 +1,263 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter is the optional YAML header of a document, delimited by ---
+// lines at the very top of the file.
+type FrontMatter struct {
+	Title   string   `yaml:"title"`
+	Date    string   `yaml:"date"`
+	Tags    []string `yaml:"tags"`
+	Summary string   `yaml:"summary"`
+}
+
+type Heading struct {
+	Level int
+	Text  string
+	// Line is the line of the rendered document the heading appears on.
+	Line int
+}
+
+type Document struct {
+	Slug     string
+	Path     string
+	Title    string
+	Date     time.Time
+	Tags     []string
+	Summary  string
+	Markdown string
+	Rendered string
+	Headings []Heading
+
+	// style is what Rendered was rendered in.
+	style  string
+	others *renderings
+}
+
+// RenderedIn returns the document rendered in the glamour style for a
+// terminal with the color profile, wrapped to width columns, with its
+// headings located in that rendering. Anything but the library's style for
+// a true color terminal at DefaultWidth is rendered on first use.
+func (d *Document) RenderedIn(style string, profile termenv.Profile, width int) (string, []Heading, error) {
+	if (style == d.style && profile == termenv.TrueColor && width == DefaultWidth) || d.others == nil {
+		return d.Rendered, d.Headings, nil
+	}
+	rendered, err := d.others.render(d.Markdown, style, profile, width)
+	if err != nil {
+		return "", nil, err
+	}
+	headings := append([]Heading(nil), d.Headings...)
+	locateHeadings(headings, rendered)
+	return rendered, headings, nil
+}
+
+func splitFrontMatter(data []byte) (FrontMatter, []byte, error) {
+	var fm FrontMatter
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if !bytes.HasPrefix(data, []byte("---\n")) && !bytes.HasPrefix(data, []byte("---\r\n")) {
+		return fm, data, nil
+	}
+	rest := data[bytes.IndexByte(data, '\n')+1:]
+	end := bytes.Index(rest, []byte("\n---"))
+	if end < 0 {
+		return fm, data, fmt.Errorf("unterminated front matter")
+	}
+	if err := yaml.Unmarshal(rest[:end], &fm); err != nil {
+		return fm, data, fmt.Errorf("front matter: %w", err)
+	}
+	body := rest[end+len("\n---"):]
+	if i := bytes.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	} else {
+		body = nil
+	}
+	return fm, body, nil
+}
+
+// ParseDocument reads a markdown document and its front matter. Documents
+// without a title fall back to their first heading, then their file name.
+func ParseDocument(path string, data []byte) (*Document, error) {
+	fm, body, err := splitFrontMatter(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	slug := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	doc := &Document{
+		Slug:     slug,
+		Path:     path,
+		Title:    fm.Title,
+		Tags:     fm.Tags,
+		Summary:  fm.Summary,
+		Markdown: string(body),
+	}
+	if fm.Date != "" {
+		if doc.Date, err = time.Parse(time.DateOnly, fm.Date); err != nil {
+			return nil, fmt.Errorf("%s: date %q must be YYYY-MM-DD", path, fm.Date)
+		}
+	}
+	headings := markdownHeadings(doc.Markdown)
+	if doc.Title == "" && len(headings) > 0 {
+		doc.Title = headings[0].Text
+	}
+	if doc.Title == "" {
+		doc.Title = slug
+	}
+	doc.Headings = headings
+	return doc, nil
+}
+
+// markdownHeadings returns the ATX headings of md, skipping fenced code.
+func markdownHeadings(md string) []Heading {
+	var (
+		headings []Heading
+		fenced   bool
+	)
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		text := strings.TrimSpace(strings.Trim(trimmed[level:], "# "))
+		if level > 6 || text == "" || (len(trimmed) > level && trimmed[level] != ' ') {
+			continue
+		}
+		headings = append(headings, Heading{Level: level, Text: text})
+	}
+	return headings
+}
+
+// locateHeadings finds the rendered line of every heading by searching the
+// ANSI-stripped output in order.
+func locateHeadings(headings []Heading, rendered string) {
+	lines := strings.Split(ansi.Strip(rendered), "\n")
+	next := 0
+	for i := range headings {
+		headings[i].Line = -1
+		for j := next; j < len(lines); j++ {
+			if strings.Contains(lines[j], headings[i].Text) {
+				headings[i].Line = j
+				next = j + 1
+				break
+			}
+		}
+	}
+}
+
+// Library is the set of markdown documents in a directory. Like Store, it
+// swaps in a complete new set on every reload.
+type Library struct {
+	dir   string
+	style string
+
+	docs atomic.Pointer[[]*Document]
+
+	mu      sync.Mutex
+	changed chan struct{}
+	lastErr error
+}
+
+func NewLibrary(dir, style string) *Library {
+	l := &Library{dir: dir, style: style, changed: make(chan struct{})}
+	l.docs.Store(&[]*Document{})
+	return l
+}
+
+func (l *Library) Dir() string {
+	return l.dir
+}
+
+// Documents returns every document, newest first.
+func (l *Library) Documents() []*Document {
+	return *l.docs.Load()
+}
+
+func (l *Library) Document(slug string) (*Document, bool) {
+	for _, d := range l.Documents() {
+		if d.Slug == slug {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+func (l *Library) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastErr
+}
+
+func (l *Library) Changed() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.changed
+}
+
+// Reload parses and renders every *.md file in the directory. Documents that
+// fail to parse are skipped and reported in the returned error.
+func (l *Library) Reload() error {
+	paths, err := filepath.Glob(filepath.Join(l.dir, "*.md"))
+	if err != nil {
+		return err
+	}
+
+	var (
+		docs []*Document
+		errs []string
+	)
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		doc, err := ParseDocument(p, data)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if doc.Rendered, err = RenderMarkdown(doc.Markdown, l.style, termenv.TrueColor, DefaultWidth); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", p, err))
+			continue
+		}
+		locateHeadings(doc.Headings, doc.Rendered)
+		doc.style, doc.others = l.style, &renderings{}
+		docs = append(docs, doc)
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		if !docs[i].Date.Equal(docs[j].Date) {
+			return docs[i].Date.After(docs[j].Date)
+		}
+		return docs[i].Title < docs[j].Title
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.docs.Store(&docs)
+	l.lastErr = nil
+	if len(errs) > 0 {
+		l.lastErr = fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+	return l.lastErr
+}
@@ -0,0 +1,78 @@
+package content
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Hit is a match found by an Index, in the page it was found in.
+type Hit struct {
+	// Title and Slug are the document's, both empty for the resume.
+	Title string
+	Slug  string
+	Match Match
+	// Nth is the match's index among the page's own matches.
+	Nth int
+}
+
+type indexEntry struct {
+	title, slug string
+	source      source
+}
+
+// Index holds the markdown of the resume and every document prepared for
+// searching. Rebuild it after either reloads; searches in flight keep using
+// the entries they started with.
+type Index struct {
+	entries atomic.Pointer[[]indexEntry]
+
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+func NewIndex() *Index {
+	ix := &Index{changed: make(chan struct{})}
+	ix.entries.Store(&[]indexEntry{})
+	return ix
+}
+
+// Rebuild indexes resume's current snapshot and library's documents, then
+// tells waiting pages to search again.
+func (ix *Index) Rebuild(resume *Store, library *Library) {
+	// Rebuilds run one at a time, so the last to finish has read the latest
+	// content.
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	var entries []indexEntry
+	if snap := resume.Current(); snap.Err == nil {
+		entries = append(entries, indexEntry{source: prepare(snap.Markdown)})
+	}
+	for _, doc := range library.Documents() {
+		entries = append(entries, indexEntry{title: doc.Title, slug: doc.Slug, source: prepare(doc.Markdown)})
+	}
+
+	ix.entries.Store(&entries)
+	close(ix.changed)
+	ix.changed = make(chan struct{})
+}
+
+// Changed returns a channel that is closed the next time the index is
+// rebuilt.
+func (ix *Index) Changed() <-chan struct{} {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.changed
+}
+
+// Search returns every match of query, the resume's first and then each
+// document's in library order.
+func (ix *Index) Search(query string) []Hit {
+	var hits []Hit
+	for _, e := range *ix.entries.Load() {
+		for n, m := range e.source.find(query) {
+			hits = append(hits, Hit{Title: e.title, Slug: e.slug, Match: m, Nth: n})
+		}
+	}
+	return hits
+}
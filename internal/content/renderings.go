@@ -0,0 +1,65 @@
+package content
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/muesli/termenv"
+)
+
+// DefaultWidth is the width content is wrapped at when it is loaded, and
+// when no width is given.
+const DefaultWidth = 80
+
+// maxRenderings bounds how many renderings of one document are kept, as
+// every window width a visitor resizes through adds one.
+const maxRenderings = 32
+
+// RenderMarkdown renders markdown in the glamour style for a terminal with
+// the color profile, wrapped to fit width columns.
+func RenderMarkdown(markdown, style string, profile termenv.Profile, width int) (string, error) {
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStylePath(style),
+		glamour.WithColorProfile(profile),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return "", err
+	}
+	return r.Render(markdown)
+}
+
+type rendering struct {
+	style   string
+	profile termenv.Profile
+	width   int
+}
+
+// renderings caches markdown rendered in glamour styles, color profiles and
+// widths other than the ones it was loaded with, for sessions using another
+// theme, terminal or window size.
+type renderings struct {
+	mu    sync.Mutex
+	byKey map[rendering]string
+}
+
+func (r *renderings) render(markdown, style string, profile termenv.Profile, width int) (string, error) {
+	key := rendering{style: style, profile: profile, width: width}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rendered, ok := r.byKey[key]; ok {
+		return rendered, nil
+	}
+	rendered, err := RenderMarkdown(markdown, style, profile, width)
+	if err != nil {
+		return "", err
+	}
+	if r.byKey == nil || len(r.byKey) >= maxRenderings {
+		r.byKey = make(map[rendering]string)
+	}
+	r.byKey[key] = rendered
+	return rendered, nil
+}
@@ -0,0 +1,38 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+func TestRenderedInWrapsToWidth(t *testing.T) {
+	s := NewStore(func() (string, error) {
+		return "# Title\n\n" + strings.Repeat("a sentence long enough to need wrapping ", 10), nil
+	}, "dark")
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	snap := s.Current()
+	lines := 0
+	for _, width := range []int{120, DefaultWidth, 60, 40} {
+		rendered, err := snap.RenderedIn("dark", termenv.TrueColor, width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		widest := 0
+		for _, line := range strings.Split(rendered, "\n") {
+			widest = max(widest, ansi.StringWidth(line))
+		}
+		if widest > width {
+			t.Errorf("rendered at %d columns, widest line is %d", width, widest)
+		}
+		if n := strings.Count(rendered, "\n"); n <= lines {
+			t.Errorf("rendered at %d columns in %d lines, no more than at a wider width", width, n)
+		} else {
+			lines = n
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+const (
+	highlightOn  = "\x1b[7m"
+	highlightOff = "\x1b[27m"
+	snippetWidth = 60
+)
+
+// Match is one case-insensitive occurrence of a query in markdown source.
+type Match struct {
+	// Line is the zero-based line of the source the occurrence starts on.
+	Line    int
+	Snippet string
+	// mark is the byte offset in the source where MatchLines marks the
+	// occurrence.
+	mark int
+}
+
+// FindMatches searches the markdown source, not its rendering, so matches
+// are the same whatever the reader's window, theme and terminal. A phrase
+// is found even where the source wraps it onto the next line.
+func FindMatches(markdown, query string) []Match {
+	return prepare(markdown).find(query)
+}
+
+// marker is put into the source at each match to see which line of the
+// rendering the match lands on. It has no width and is no place to break a
+// line, so the marked source wraps exactly as the plain one does.
+const marker = "\u200b"
+
+// MatchLines renders markdown as RenderMarkdown does and returns the line of
+// the rendering each of FindMatches' matches starts on, in order. A match in
+// markup the renderer leaves out, such as a link reference definition, is
+// given the line of the match before it.
+func MatchLines(markdown, query, style string, profile termenv.Profile, width int) ([]int, error) {
+	matches := FindMatches(markdown, query)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	var b strings.Builder
+	prev := 0
+	for _, m := range matches {
+		b.WriteString(markdown[prev:m.mark])
+		b.WriteString(marker)
+		prev = m.mark
+	}
+	b.WriteString(markdown[prev:])
+	rendered, err := RenderMarkdown(b.String(), style, profile, width)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]int, 0, len(matches))
+	for i, line := range strings.Split(rendered, "\n") {
+		for n := strings.Count(line, marker); n > 0; n-- {
+			lines = append(lines, i)
+		}
+	}
+	for len(lines) < len(matches) {
+		last := 0
+		if n := len(lines); n > 0 {
+			last = lines[n-1]
+		}
+		lines = append(lines, last)
+	}
+	return lines[:len(matches)], nil
+}
+
+// source is markdown prepared for searching.
+type source struct {
+	lines [][]rune
+	// starts is the byte offset of each line in text.
+	starts []int
+	flat   flattened
+}
+
+func prepare(markdown string) source {
+	var s source
+	offset := 0
+	for _, line := range strings.Split(markdown, "\n") {
+		s.lines = append(s.lines, []rune(line))
+		s.starts = append(s.starts, offset)
+		offset += len(line) + 1
+	}
+	s.flat = flatten(s.lines)
+	return s
+}
+
+func (s source) find(query string) []Match {
+	var matches []Match
+	for _, o := range s.flat.find(query) {
+		start := s.flat.pos[o[0]]
+		line := s.lines[start.line]
+		col := len(string(line[:start.col]))
+		matches = append(matches, Match{
+			Line:    start.line,
+			Snippet: snippet(string(line), col, len(query)),
+			mark:    s.mark(s.flat.pos[o[0]:o[1]]),
+		})
+	}
+	return matches
+}
+
+// mark is where to put the marker for an occurrence spanning pos: after
+// its first letter or digit, so that it never splits the punctuation that
+// starts a heading, list item or other block.
+func (s source) mark(pos []position) int {
+	for _, p := range pos {
+		if p.col < 0 {
+			continue
+		}
+		if r := s.lines[p.line][p.col]; unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return s.starts[p.line] + len(string(s.lines[p.line][:p.col+1]))
+		}
+	}
+	p := pos[0]
+	return s.starts[p.line] + len(string(s.lines[p.line][:p.col]))
+}
+
+func snippet(line string, start, n int) string {
+	trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+	start = max(0, start-(len(line)-len(trimmed)))
+	line = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	if utf8.RuneCountInString(line) <= snippetWidth {
+		return line
+	}
+	lo := max(0, start-snippetWidth/2)
+	hi := min(len(line), lo+snippetWidth)
+	for lo > 0 && !utf8.RuneStart(line[lo]) {
+		lo--
+	}
+	for hi < len(line) && !utf8.RuneStart(line[hi]) {
+		hi++
+	}
+	s := line[lo:hi]
+	if lo > 0 {
+		s = "…" + s
+	}
+	if hi < len(line) {
+		s += "…"
+	}
+	return s
+}
+
+// Highlight marks every visible occurrence of query in rendered, which may
+// contain ANSI styling. An occurrence may run on past the end of a line
+// where the renderer wrapped a paragraph.
+func Highlight(rendered, query string) string {
+	if query == "" {
+		return rendered
+	}
+	lines := strings.Split(rendered, "\n")
+	t := flatten(visibleLines(rendered))
+
+	spans := make(map[int][][2]int)
+	for _, o := range t.find(query) {
+		for _, p := range t.pos[o[0]:o[1]] {
+			if p.col < 0 {
+				continue
+			}
+			s := spans[p.line]
+			if n := len(s); n > 0 && s[n-1][1] == p.col {
+				s[n-1][1]++
+			} else {
+				s = append(s, [2]int{p.col, p.col + 1})
+			}
+			spans[p.line] = s
+		}
+	}
+	for i, s := range spans {
+		lines[i] = highlightSpans(lines[i], s)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func visibleLines(rendered string) [][]rune {
+	raw := strings.Split(rendered, "\n")
+	lines := make([][]rune, len(raw))
+	for i, line := range raw {
+		lines[i] = []rune(ansi.Strip(line))
+	}
+	return lines
+}
+
+// position is where a rune of flattened text came from. Runes put
+// in between lines have no column.
+type position struct {
+	line, col int
+}
+
+// flattened is the text of a document or its rendering, lowercased, with
+// each line stripped of its margins and joined to the next by a space, so
+// that phrases wrapped onto the next line are still found. Blank lines join
+// paragraphs with a newline, which no query matches.
+type flattened struct {
+	text []rune
+	pos  []position
+}
+
+func flatten(lines [][]rune) flattened {
+	var t flattened
+	for i, line := range lines {
+		first, last := 0, len(line)-1
+		for first <= last && unicode.IsSpace(line[first]) {
+			first++
+		}
+		for last >= first && unicode.IsSpace(line[last]) {
+			last--
+		}
+		if first > last {
+			t.add('\n', position{line: i, col: -1})
+			continue
+		}
+		if n := len(t.text); n > 0 && t.text[n-1] != '\n' {
+			t.add(' ', position{line: i, col: -1})
+		}
+		for j := first; j <= last; j++ {
+			t.add(unicode.ToLower(line[j]), position{line: i, col: j})
+		}
+	}
+	return t
+}
+
+func (t *flattened) add(r rune, p position) {
+	t.text = append(t.text, r)
+	t.pos = append(t.pos, p)
+}
+
+// find returns the start and end of every non-overlapping occurrence of
+// query.
+func (t flattened) find(query string) [][2]int {
+	needle := []rune(query)
+	if len(needle) == 0 {
+		return nil
+	}
+	for i, r := range needle {
+		needle[i] = unicode.ToLower(r)
+	}
+	var found [][2]int
+	for j := 0; j+len(needle) <= len(t.text); {
+		if runesEqual(t.text[j:j+len(needle)], needle) {
+			found = append(found, [2]int{j, j + len(needle)})
+			j += len(needle)
+			continue
+		}
+		j++
+	}
+	return found
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// highlightSpans wraps the visible rune ranges in spans with reverse video,
+// re-enabling it after any escape sequence inside a span in case that
+// sequence reset the style.
+func highlightSpans(line string, spans [][2]int) string {
+	var (
+		b       strings.Builder
+		visible int
+		span    int
+		inSpan  bool
+	)
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			n := escapeLen(line[i:])
+			b.WriteString(line[i : i+n])
+			if inSpan {
+				b.WriteString(highlightOn)
+			}
+			i += n
+			continue
+		}
+		if span < len(spans) && !inSpan && visible == spans[span][0] {
+			b.WriteString(highlightOn)
+			inSpan = true
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+size])
+		i += size
+		visible++
+		if inSpan && visible == spans[span][1] {
+			b.WriteString(highlightOff)
+			inSpan = false
+			span++
+		}
+	}
+	if inSpan {
+		b.WriteString(highlightOff)
+	}
+	return b.String()
+}
+
+// escapeLen returns the length of the escape sequence at the start of s.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
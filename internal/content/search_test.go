@@ -0,0 +1,125 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+const needles = `# Needles
+
+See [the needle guide](https://example.com/needle) first.
+
+This sentence runs long enough that the words threaded needle sit across a wrapped line.
+
+A *needle* again.
+`
+
+func TestFindMatches(t *testing.T) {
+	tests := []struct {
+		query string
+		lines []int
+	}{
+		{"needle", []int{0, 2, 2, 4, 6}},
+		{"NEEDLE GUIDE", []int{2}},
+		{"example.com/needle", []int{2}},
+		{"enough that the words", []int{4}},
+		{"haystack", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var lines []int
+			for _, m := range FindMatches(needles, tt.query) {
+				lines = append(lines, m.Line)
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Errorf("lines = %v, want %v", lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestMatchLines(t *testing.T) {
+	for _, width := range []int{40, DefaultWidth} {
+		for _, query := range []string{"needle", "enough that the words", "a wrapped line"} {
+			rendered, err := RenderMarkdown(needles, "notty", termenv.Ascii, width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.ToLower(ansi.Strip(rendered)), "\n")
+
+			hits, err := MatchLines(needles, query, "notty", termenv.Ascii, width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := len(FindMatches(needles, query)); len(hits) != want {
+				t.Fatalf("%q at %d columns: %d lines for %d matches", query, width, len(hits), want)
+			}
+			first := strings.Fields(query)[0]
+			for i, line := range hits {
+				if line < 0 || line >= len(lines) || !strings.Contains(lines[line], first) {
+					t.Errorf("%q at %d columns: match %d is on rendered line %d", query, width, i, line)
+				}
+			}
+		}
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	resume := NewStore(func() (string, error) { return "# Dragon\n\nThreads a needle.\n", nil }, "notty")
+	if err := resume.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "needles.md"), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(needles)
+	library := NewLibrary(dir, "notty")
+	if err := library.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	ix := NewIndex()
+	if hits := ix.Search("needle"); len(hits) != 0 {
+		t.Fatalf("empty index found %d hits", len(hits))
+	}
+	changed := ix.Changed()
+	ix.Rebuild(resume, library)
+	select {
+	case <-changed:
+	default:
+		t.Fatal("Rebuild did not signal a change")
+	}
+
+	hits := ix.Search("needle")
+	if len(hits) != 6 {
+		t.Fatalf("got %d hits, want 6", len(hits))
+	}
+	if h := hits[0]; h.Slug != "" || h.Nth != 0 || h.Match.Line != 2 {
+		t.Errorf("first hit = %+v, want the resume's", h)
+	}
+	for i, h := range hits[1:] {
+		if h.Slug != "needles" || h.Title != "Needles" || h.Nth != i {
+			t.Errorf("hit %d = %+v", i+1, h)
+		}
+	}
+
+	write("# Needles\n\nNothing sharp here.\n")
+	if err := library.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	ix.Rebuild(resume, library)
+	if hits := ix.Search("needle"); len(hits) != 2 {
+		t.Errorf("after the rebuild got %d hits, want 2", len(hits))
+	}
+}
@@ -0,0 +1,110 @@
+// Package content keeps rendered markdown in memory and swaps in a new
+// rendering whenever its source changes, so open sessions can pick it up
+// without a restart.
+package content
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/muesli/termenv"
+)
+
+// Snapshot is one rendering of a document. Snapshots are immutable; a reload
+// replaces the whole snapshot.
+type Snapshot struct {
+	Markdown string
+	Rendered string
+	Version  uint64
+	LoadedAt time.Time
+	Err      error
+
+	// style is what Rendered was rendered in.
+	style  string
+	others *renderings
+}
+
+// RenderedIn returns the document rendered in the glamour style for a
+// terminal with the color profile, wrapped to width columns. Rendered is in
+// the store's style for a true color terminal at DefaultWidth; anything else
+// is rendered on first use.
+func (s *Snapshot) RenderedIn(style string, profile termenv.Profile, width int) (string, error) {
+	if s.Err != nil || (style == s.style && profile == termenv.TrueColor && width == DefaultWidth) || s.others == nil {
+		return s.Rendered, s.Err
+	}
+	return s.others.render(s.Markdown, style, profile, width)
+}
+
+// Notifier is implemented by Store and Library.
+type Notifier interface {
+	// Changed returns a channel that is closed the next time the content
+	// is replaced.
+	Changed() <-chan struct{}
+}
+
+// Loader returns the current markdown source of a document.
+type Loader func() (string, error)
+
+type Store struct {
+	load  Loader
+	style string
+
+	current atomic.Pointer[Snapshot]
+
+	// reloading keeps reloads from publishing out of order when more than
+	// one watcher notices a change.
+	reloading sync.Mutex
+
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+func NewStore(load Loader, style string) *Store {
+	s := &Store{
+		load:    load,
+		style:   style,
+		changed: make(chan struct{}),
+	}
+	s.current.Store(&Snapshot{})
+	return s
+}
+
+// Current returns the latest snapshot. It never returns nil.
+func (s *Store) Current() *Snapshot {
+	return s.current.Load()
+}
+
+// Changed returns a channel that is closed the next time the snapshot is
+// replaced.
+func (s *Store) Changed() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed
+}
+
+// Reload loads and renders the document, then publishes it. A failed load is
+// published too, so pages can show the error, but an unchanged document is
+// not republished.
+func (s *Store) Reload() error {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
+
+	snap := &Snapshot{LoadedAt: time.Now(), style: s.style, others: &renderings{}}
+	snap.Markdown, snap.Err = s.load()
+	if snap.Err == nil {
+		snap.Rendered, snap.Err = RenderMarkdown(snap.Markdown, s.style, termenv.TrueColor, DefaultWidth)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.current.Load()
+	if prev.Version > 0 && prev.Err == nil && snap.Err == nil && prev.Markdown == snap.Markdown {
+		return nil
+	}
+	snap.Version = prev.Version + 1
+	s.current.Store(snap)
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return snap.Err
+}
@@ -0,0 +1,100 @@
+package content
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	count   int
+	exists  bool
+}
+
+// statFile summarises path. For a directory it combines the state of every
+// file directly inside it, so additions and removals count as changes.
+func statFile(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	if !fi.IsDir() {
+		return fileState{modTime: fi.ModTime(), size: fi.Size(), count: 1, exists: true}
+	}
+
+	st := fileState{modTime: fi.ModTime(), exists: true}
+	entries, _ := os.ReadDir(path)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+		st.count++
+		st.size += info.Size()
+		if info.ModTime().After(st.modTime) {
+			st.modTime = info.ModTime()
+		}
+	}
+	return st
+}
+
+// Watch polls paths every interval and calls onChange when any of them is
+// created, modified or removed. Directories are watched one level deep.
+// Polling keeps working on network and container-mounted file systems where
+// inotify events are unreliable.
+func Watch(ctx context.Context, interval time.Duration, paths []string, onChange func()) {
+	states := make(map[string]fileState, len(paths))
+	for _, p := range paths {
+		states[p] = statFile(p)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed := false
+		for _, p := range paths {
+			if st := statFile(p); st != states[p] {
+				states[p] = st
+				changed = true
+			}
+		}
+		if changed {
+			onChange()
+		}
+	}
+}
+
+// Poll calls read every interval and onChange when it returns something
+// other than it did the last time, such as a counter in a database that
+// every write bumps. Ticks on which read fails are skipped.
+func Poll[T comparable](ctx context.Context, interval time.Duration, read func() (T, error), onChange func()) {
+	last, err := read()
+	ok := err == nil
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		v, err := read()
+		if err != nil {
+			continue
+		}
+		if ok && v != last {
+			onChange()
+		}
+		last, ok = v, true
+	}
+}
@@ -0,0 +1,51 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	// Each tick reads the next value; an error skips the tick.
+	reads := []struct {
+		v   int
+		err error
+	}{
+		{1, nil},                  // the starting value
+		{1, nil},                  // unchanged
+		{0, errors.New("locked")}, // skipped
+		{2, nil},                  // changed
+		{2, nil},                  // unchanged
+		{0, errors.New("locked")}, // skipped
+		{3, nil},                  // changed
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	read := make(chan struct{})
+	n := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Poll(ctx, time.Millisecond, func() (int, error) {
+			r := reads[n]
+			n++
+			if n == len(reads) {
+				defer cancel()
+			}
+			read <- struct{}{}
+			return r.v, r.err
+		}, func() { calls.Add(1) })
+	}()
+	for range reads {
+		<-read
+	}
+	<-done
+	if got := calls.Load(); got != 2 {
+		t.Errorf("onChange called %d times, want 2", got)
+	}
+}
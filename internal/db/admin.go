@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Admin is an SSH key allowed into the admin pages.
+type Admin struct {
+	Fingerprint string
+	Name        string
+	CreatedAt   time.Time
+}
+
+var ErrAdminNotFound = errors.New("admin not found")
+
+func (db *Database) ListAdmins(ctx context.Context) ([]Admin, error) {
+	rows, err := db.QueryContext(ctx, `SELECT fingerprint, name, created_at FROM authorized_admins ORDER BY created_at, fingerprint`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admins: %w", err)
+	}
+	defer rows.Close()
+
+	var admins []Admin
+	for rows.Next() {
+		var a Admin
+		if err := rows.Scan(&a.Fingerprint, &a.Name, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		admins = append(admins, a)
+	}
+	return admins, rows.Err()
+}
+
+// AddAdmin authorizes fingerprint, renaming it if it is already there.
+func (db *Database) AddAdmin(ctx context.Context, fingerprint, name string) error {
+	_, err := db.ExecContext(ctx, `
+	INSERT INTO authorized_admins (fingerprint, name, created_at) VALUES (?, ?, ?)
+	ON CONFLICT(fingerprint) DO UPDATE SET name = excluded.name
+	`, fingerprint, name, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to add admin: %w", err)
+	}
+	return nil
+}
+
+func (db *Database) RemoveAdmin(ctx context.Context, fingerprint string) error {
+	res, err := db.ExecContext(ctx, `DELETE FROM authorized_admins WHERE fingerprint = ?`, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to remove admin: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAdminNotFound
+	}
+	return nil
+}
+
+func (db *Database) IsAuthorizedAdmin(ctx context.Context, fingerprint string) (bool, error) {
+	var one int
+	err := db.QueryRowContext(ctx, `SELECT 1 FROM authorized_admins WHERE fingerprint = ?`, fingerprint).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check admin: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AuditEntry is something a session did: connecting, opening a page, sending
+// a message or an admin action. Admin actions start with "admin.".
+type AuditEntry struct {
+	ID          int64
+	Session     string
+	Fingerprint string
+	RemoteAddr  string
+	Action      string
+	Detail      string
+	CreatedAt   time.Time
+}
+
+// AuditQuery narrows ListAudit down. Zero fields match everything.
+type AuditQuery struct {
+	Session     string
+	Fingerprint string
+	// Action matches the action itself or, for a prefix such as "admin",
+	// every action under it.
+	Action string
+	Since  time.Time
+	// Limit is how many of the latest entries to return.
+	Limit int
+}
+
+func (db *Database) RecordAudit(ctx context.Context, e AuditEntry) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	_, err := db.ExecContext(ctx, `
+	INSERT INTO audit_log (session, fingerprint, remote_addr, action, detail, created_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`, e.Session, e.Fingerprint, e.RemoteAddr, e.Action, e.Detail, e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record %s: %w", e.Action, err)
+	}
+	return nil
+}
+
+// ListAudit returns the entries matching q, oldest first.
+func (db *Database) ListAudit(ctx context.Context, q AuditQuery) ([]AuditEntry, error) {
+	var where []string
+	var args []any
+	if q.Session != "" {
+		where = append(where, "session = ?")
+		args = append(args, q.Session)
+	}
+	if q.Fingerprint != "" {
+		where = append(where, "fingerprint = ?")
+		args = append(args, q.Fingerprint)
+	}
+	if q.Action != "" {
+		where = append(where, "(action = ? OR action LIKE ?)")
+		args = append(args, q.Action, q.Action+".%")
+	}
+	if !q.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, q.Since.UTC())
+	}
+	query := `SELECT id, session, fingerprint, remote_addr, action, detail, created_at FROM audit_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.Session, &e.Fingerprint, &e.RemoteAddr, &e.Action, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// The query takes the latest entries; show them in the order they
+	// happened.
+	slices.Reverse(entries)
+	return entries, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BlockKind string
+
+const (
+	BlockEmail BlockKind = "email"
+	BlockKey   BlockKind = "key"
+	BlockIP    BlockKind = "ip"
+)
+
+// BlockEntry is a sender whose contact messages are refused.
+type BlockEntry struct {
+	Kind      BlockKind
+	Value     string
+	Reason    string
+	CreatedAt time.Time
+}
+
+var ErrNotBlocked = errors.New("not blocked")
+
+// normalizeBlocked makes email addresses match regardless of case.
+func normalizeBlocked(kind BlockKind, value string) string {
+	value = strings.TrimSpace(value)
+	if kind == BlockEmail {
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+func (db *Database) ListBlocked(ctx context.Context) ([]BlockEntry, error) {
+	rows, err := db.QueryContext(ctx, `SELECT kind, value, reason, created_at FROM blocklist ORDER BY kind, value`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocklist: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []BlockEntry
+	for rows.Next() {
+		var e BlockEntry
+		if err := rows.Scan(&e.Kind, &e.Value, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Block adds value to the blocklist, updating the reason if it is already
+// there.
+func (db *Database) Block(ctx context.Context, kind BlockKind, value, reason string) error {
+	value = normalizeBlocked(kind, value)
+	_, err := db.ExecContext(ctx, `
+	INSERT INTO blocklist (kind, value, reason, created_at) VALUES (?, ?, ?, ?)
+	ON CONFLICT(kind, value) DO UPDATE SET reason = excluded.reason
+	`, kind, value, reason, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to block %s %s: %w", kind, value, err)
+	}
+	return nil
+}
+
+func (db *Database) Unblock(ctx context.Context, kind BlockKind, value string) error {
+	value = normalizeBlocked(kind, value)
+	res, err := db.ExecContext(ctx, `DELETE FROM blocklist WHERE kind = ? AND value = ?`, kind, value)
+	if err != nil {
+		return fmt.Errorf("failed to unblock %s %s: %w", kind, value, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotBlocked
+	}
+	return nil
+}
+
+// IsBlocked reports whether value is on the blocklist as kind.
+func (db *Database) IsBlocked(ctx context.Context, kind BlockKind, value string) (bool, error) {
+	value = normalizeBlocked(kind, value)
+	var one int
+	err := db.QueryRowContext(ctx, `SELECT 1 FROM blocklist WHERE kind = ? AND value = ?`, kind, value).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check blocklist: %w", err)
+	}
+	return true, nil
+}
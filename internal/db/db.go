@@ -3,11 +3,9 @@ package db
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"github.com/charmbracelet/log"
 	_ "github.com/mattn/go-sqlite3"
-	"log"
-	"os"
 	"strconv"
 	"time"
 )
@@ -16,76 +14,53 @@ type Database struct {
 	*sql.DB
 }
 
-func InitDatabase(dbPath string) (*Database, error) {
-	_, err := os.Stat(dbPath)
-	dbExists := !errors.Is(err, os.ErrNotExist)
+// OpenDatabase connects to the SQLite file at dbPath without touching its
+// schema.
+func OpenDatabase(dbPath string) (*Database, error) {
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, err
 	}
-
-	database := &Database{db}
-	if !dbExists {
-		if err := database.initSchema(); err != nil {
-			db.Close()
-			return nil, fmt.Errorf("failed to initialize schema: %w", err)
-		}
-	}
-	return database, nil
+	return &Database{db}, nil
 }
 
-func (db *Database) initSchema() error {
-	// Create users table
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS users (
-	id INTEGER PRIMARY KEY AUTOINCREMENT,
-	username TEXT NOT NULL UNIQUE,
-	email TEXT NOT NULL UNIQUE,
-	password TEXT NOT NULL,
-	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);
-	`
-
-	_, err := db.Exec(createTableSQL)
+// InitDatabase opens the database and applies any pending migrations.
+func InitDatabase(dbPath string) (*Database, error) {
+	database, err := OpenDatabase(dbPath)
 	if err != nil {
-		return fmt.Errorf("failed to create users table: %w", err)
+		return nil, err
 	}
 
-	// Add any additional schema initialization here
-	// For example, creating indexes:
-	createIndexSQL := `
-	CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
-	CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
-	`
-
-	_, err = db.Exec(createIndexSQL)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	applied, err := database.MigrateUp(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create indexes: %w", err)
+		database.DB.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
-
-	return nil
+	for _, m := range applied {
+		log.Info("Applied migration", "version", m.Version, "name", m.Name)
+	}
+	return database, nil
 }
 
-func (db *Database) CheckHealth() map[string]string {
-
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+// CheckHealth pings the database and describes its connection pool. It
+// returns an error if the database can't be reached within a second, or
+// within ctx if that ends sooner.
+func (db *Database) CheckHealth(ctx context.Context) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
-	stats := make(map[string]string)
-
-	err := db.PingContext(ctx)
-	if err != nil {
-		stats["status"] = "down"
-		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err) // Log the error and terminate the program
-		return stats
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("database is down: %w", err)
 	}
 
-	stats["status"] = "up"
+	stats := make(map[string]string)
 	stats["message"] = "It's healthy"
 
 	dbStats := db.Stats()
@@ -113,10 +88,10 @@ func (db *Database) CheckHealth() map[string]string {
 		stats["message"] = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
 	}
 
-	return stats
+	return stats, nil
 }
 
 func (db *Database) Close() error {
-	log.Printf("Disconnected from database")
-	return db.Close()
+	log.Info("Disconnected from database")
+	return db.DB.Close()
 }
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// MenuItem overrides how one entry of the main menu is shown. Empty titles
+// and descriptions keep the built-in text.
+type MenuItem struct {
+	Key         string
+	Title       string
+	Description string
+	Position    int
+	Hidden      bool
+}
+
+func (db *Database) ListMenuItems(ctx context.Context) ([]MenuItem, error) {
+	rows, err := db.QueryContext(ctx, `SELECT key, title, description, position, hidden FROM menu_items ORDER BY position, key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list menu items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []MenuItem
+	for rows.Next() {
+		var i MenuItem
+		if err := rows.Scan(&i.Key, &i.Title, &i.Description, &i.Position, &i.Hidden); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+// SaveMenuItems replaces every override in one transaction, so a reordered
+// menu is never seen half saved.
+func (db *Database) SaveMenuItems(ctx context.Context, items []MenuItem) error {
+	err := db.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM menu_items`); err != nil {
+			return err
+		}
+		for _, i := range items {
+			_, err := tx.ExecContext(ctx, `
+			INSERT INTO menu_items (key, title, description, position, hidden) VALUES (?, ?, ?, ?, ?)
+			`, i.Key, i.Title, i.Description, i.Position, i.Hidden)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save menu items: %w", err)
+	}
+	return nil
+}
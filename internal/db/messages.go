@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliveryQueued  DeliveryStatus = "queued"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+	// DeliveryQuarantined messages were flagged as likely spam and are only
+	// emailed once approved; DeliveryError says why they were flagged.
+	DeliveryQuarantined DeliveryStatus = "quarantined"
+)
+
+// Message is a contact form submission together with who sent it and whether
+// it reached the inbox by email.
+type Message struct {
+	ID             int64
+	Name           string
+	Email          string
+	Body           string
+	SSHUser        string
+	KeyFingerprint string
+	RemoteAddr     string
+	DeliveryStatus DeliveryStatus
+	DeliveryError  string
+	Read           bool
+	CreatedAt      time.Time
+}
+
+var ErrMessageNotFound = errors.New("message not found")
+
+const messageColumns = `id, name, email, body, ssh_user, key_fingerprint, remote_addr,
+	delivery_status, delivery_error, read, created_at`
+
+func scanMessage(row interface{ Scan(...any) error }) (Message, error) {
+	var m Message
+	err := row.Scan(&m.ID, &m.Name, &m.Email, &m.Body, &m.SSHUser, &m.KeyFingerprint, &m.RemoteAddr,
+		&m.DeliveryStatus, &m.DeliveryError, &m.Read, &m.CreatedAt)
+	return m, err
+}
+
+func (db *Database) CreateMessage(ctx context.Context, m *Message) error {
+	if m.DeliveryStatus == "" {
+		m.DeliveryStatus = DeliveryPending
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	res, err := db.ExecContext(ctx, `
+	INSERT INTO messages (name, email, body, ssh_user, key_fingerprint, remote_addr, delivery_status, delivery_error, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, m.Name, m.Email, m.Body, m.SSHUser, m.KeyFingerprint, m.RemoteAddr, m.DeliveryStatus, m.DeliveryError, m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	m.ID, err = res.LastInsertId()
+	return err
+}
+
+// ListMessages returns every message, newest first.
+func (db *Database) ListMessages(ctx context.Context) ([]Message, error) {
+	rows, err := db.QueryContext(ctx, `SELECT `+messageColumns+` FROM messages ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (db *Database) GetMessage(ctx context.Context, id int64) (*Message, error) {
+	m, err := scanMessage(db.QueryRowContext(ctx, `SELECT `+messageColumns+` FROM messages WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	return &m, nil
+}
+
+func (db *Database) SetMessageDeliveryStatus(ctx context.Context, id int64, status DeliveryStatus, deliveryErr string) error {
+	return db.updateMessage(ctx, `UPDATE messages SET delivery_status = ?, delivery_error = ? WHERE id = ?`, status, deliveryErr, id)
+}
+
+func (db *Database) MarkMessageRead(ctx context.Context, id int64, read bool) error {
+	return db.updateMessage(ctx, `UPDATE messages SET read = ? WHERE id = ?`, read, id)
+}
+
+func (db *Database) DeleteMessage(ctx context.Context, id int64) error {
+	return db.updateMessage(ctx, `DELETE FROM messages WHERE id = ?`, id)
+}
+
+func (db *Database) updateMessage(ctx context.Context, query string, args ...any) error {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
+// HasRecentDuplicate reports whether the same body was sent since since by
+// the same email address or, if fingerprint is set, the same key.
+func (db *Database) HasRecentDuplicate(ctx context.Context, body, email, fingerprint string, since time.Time) (bool, error) {
+	var one int
+	err := db.QueryRowContext(ctx, `
+	SELECT 1 FROM messages
+	WHERE body = ? AND created_at >= ? AND (email = ? OR (? != '' AND key_fingerprint = ?))
+	LIMIT 1
+	`, body, since.UTC(), email, fingerprint, fingerprint).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look for duplicate messages: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// baselineSchema is the users-only schema databases had before migrations.
+const baselineSchema = `
+CREATE TABLE users (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+username TEXT NOT NULL UNIQUE,
+email TEXT NOT NULL UNIQUE,
+password TEXT NOT NULL,
+created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_users_email ON users(email);
+CREATE INDEX idx_users_username ON users(username);
+INSERT INTO users (username, email, password, created_at) VALUES
+('alice', 'alice@example.com', 'hash-a', '2024-01-02 03:04:05'),
+('bob', 'bob@example.com', 'hash-b', '2024-05-06 07:08:09');
+`
+
+type baselineUser struct {
+	ID                        int64
+	Username, Email, Password string
+	CreatedAt                 string
+}
+
+func baselineUsers(t *testing.T, db *Database) []baselineUser {
+	t.Helper()
+	rows, err := db.Query(`SELECT id, username, email, password, strftime('%Y-%m-%d %H:%M:%S', created_at) FROM users ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var users []baselineUser
+	for rows.Next() {
+		var u baselineUser
+		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.CreatedAt); err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return users
+}
+
+func TestMigrateBaselineDatabase(t *testing.T) {
+	ctx := context.Background()
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "baseline.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(baselineSchema); err != nil {
+		t.Fatal(err)
+	}
+	want := baselineUsers(t, db)
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reverting everything after 0001 undoes the users table rebuild in
+	// 0005 but keeps the table itself.
+	var sinceUsers int
+	for _, m := range migrations {
+		if m.Version > 1 {
+			sinceUsers++
+		}
+	}
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"up", func() error { _, err := db.MigrateUp(ctx); return err }},
+		{"down", func() error { _, err := db.MigrateDown(ctx, sinceUsers); return err }},
+		{"up again", func() error { _, err := db.MigrateUp(ctx); return err }},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := baselineUsers(t, db); !reflect.DeepEqual(got, want) {
+			t.Fatalf("after %s users are %+v, want %+v", step.name, got, want)
+		}
+	}
+
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range status {
+		if !s.Applied {
+			t.Errorf("migration %04d_%s not applied", s.Version, s.Name)
+		}
+	}
+
+	// The rebuilt table takes key-only visitors.
+	if _, err := db.Exec(`INSERT INTO users (username, key_fingerprint) VALUES ('carol', 'SHA256:carol')`); err != nil {
+		t.Fatalf("inserting a key-only user: %v", err)
+	}
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS links;
+DROP TABLE IF EXISTS education;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS experience;
+DROP TABLE IF EXISTS skills;
+DROP TABLE IF EXISTS profile;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS profile (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name TEXT NOT NULL,
+headline TEXT NOT NULL DEFAULT '',
+email TEXT NOT NULL DEFAULT '',
+location TEXT NOT NULL DEFAULT '',
+summary TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS skills (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+category TEXT NOT NULL DEFAULT '',
+name TEXT NOT NULL,
+position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS experience (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+company TEXT NOT NULL,
+role TEXT NOT NULL,
+location TEXT NOT NULL DEFAULT '',
+start_date TEXT NOT NULL DEFAULT '',
+end_date TEXT NOT NULL DEFAULT '',
+description TEXT NOT NULL DEFAULT '',
+position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name TEXT NOT NULL,
+description TEXT NOT NULL DEFAULT '',
+url TEXT NOT NULL DEFAULT '',
+tech TEXT NOT NULL DEFAULT '',
+position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS education (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+institution TEXT NOT NULL,
+degree TEXT NOT NULL DEFAULT '',
+start_date TEXT NOT NULL DEFAULT '',
+end_date TEXT NOT NULL DEFAULT '',
+description TEXT NOT NULL DEFAULT '',
+position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS links (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+label TEXT NOT NULL,
+url TEXT NOT NULL,
+position INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS messages (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name TEXT NOT NULL,
+email TEXT NOT NULL,
+body TEXT NOT NULL,
+ssh_user TEXT NOT NULL DEFAULT '',
+key_fingerprint TEXT NOT NULL DEFAULT '',
+remote_addr TEXT NOT NULL DEFAULT '',
+delivery_status TEXT NOT NULL DEFAULT 'pending',
+delivery_error TEXT NOT NULL DEFAULT '',
+read INTEGER NOT NULL DEFAULT 0,
+created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_created_at ON messages(created_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS outbox (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+message_id INTEGER,
+sender TEXT NOT NULL DEFAULT '',
+recipients TEXT NOT NULL DEFAULT '',
+reply_to TEXT NOT NULL DEFAULT '',
+subject TEXT NOT NULL,
+text_body TEXT NOT NULL DEFAULT '',
+html_body TEXT NOT NULL DEFAULT '',
+status TEXT NOT NULL DEFAULT 'queued',
+attempts INTEGER NOT NULL DEFAULT 0,
+last_error TEXT NOT NULL DEFAULT '',
+next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+sent_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(status, next_attempt_at);
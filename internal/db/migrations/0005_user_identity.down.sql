@@ -0,0 +1,21 @@
+-- Key-only visitors have no email or password; they are dropped rather than
+-- given made-up credentials.
+CREATE TABLE users_old (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+username TEXT NOT NULL UNIQUE,
+email TEXT NOT NULL UNIQUE,
+password TEXT NOT NULL,
+created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT OR IGNORE INTO users_old (id, username, email, password, created_at)
+SELECT id, username, email, password, created_at FROM users
+WHERE email IS NOT NULL AND password IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_username;
+DROP INDEX IF EXISTS idx_users_email;
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
+
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
@@ -0,0 +1,25 @@
+-- Visitors are identified by their SSH public key rather than a password, so
+-- the table is rebuilt with nullable email and password columns (SQLite
+-- cannot drop NOT NULL in place) and usernames no longer need to be unique.
+CREATE TABLE users_new (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+username TEXT NOT NULL,
+email TEXT UNIQUE,
+password TEXT,
+key_fingerprint TEXT UNIQUE,
+name TEXT NOT NULL DEFAULT '',
+visits INTEGER NOT NULL DEFAULT 0,
+last_seen_at DATETIME,
+created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO users_new (id, username, email, password, created_at)
+SELECT id, username, email, password, created_at FROM users;
+
+DROP INDEX IF EXISTS idx_users_username;
+DROP INDEX IF EXISTS idx_users_email;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
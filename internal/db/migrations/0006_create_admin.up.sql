@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS authorized_admins (
+fingerprint TEXT PRIMARY KEY,
+name TEXT NOT NULL DEFAULT '',
+created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Overrides for the main menu, keyed by item. Items without a row keep their
+-- built-in title, description and order.
+CREATE TABLE IF NOT EXISTS menu_items (
+key TEXT PRIMARY KEY,
+title TEXT NOT NULL DEFAULT '',
+description TEXT NOT NULL DEFAULT '',
+position INTEGER NOT NULL DEFAULT 0,
+hidden INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,7 @@
+-- Fixed-window counters for rate limits that must survive a restart, such as
+-- contact submissions. key names what is limited, e.g. "contact:ip:1.2.3.4".
+CREATE TABLE IF NOT EXISTS rate_limits (
+key TEXT PRIMARY KEY,
+window_start DATETIME NOT NULL,
+count INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_messages_email;
+DROP TABLE IF EXISTS blocklist;
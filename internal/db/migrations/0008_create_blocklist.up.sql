@@ -0,0 +1,10 @@
+-- Senders whose contact messages are refused. kind is email, key or ip.
+CREATE TABLE IF NOT EXISTS blocklist (
+kind TEXT NOT NULL,
+value TEXT NOT NULL,
+reason TEXT NOT NULL DEFAULT '',
+created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (kind, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_email ON messages(email);
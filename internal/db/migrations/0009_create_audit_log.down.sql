@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_log_session;
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+DROP TABLE IF EXISTS audit_log;
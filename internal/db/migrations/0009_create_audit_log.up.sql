@@ -0,0 +1,14 @@
+-- What visitors and admins did, keyed by the session's correlation ID so it
+-- can be matched with the server log.
+CREATE TABLE IF NOT EXISTS audit_log (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+session TEXT NOT NULL DEFAULT '',
+fingerprint TEXT NOT NULL DEFAULT '',
+remote_addr TEXT NOT NULL DEFAULT '',
+action TEXT NOT NULL,
+detail TEXT NOT NULL DEFAULT '',
+created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_session ON audit_log(session);
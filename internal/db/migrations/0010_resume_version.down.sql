@@ -0,0 +1,19 @@
+DROP TRIGGER IF EXISTS profile_insert_bumps_resume_version;
+DROP TRIGGER IF EXISTS profile_update_bumps_resume_version;
+DROP TRIGGER IF EXISTS profile_delete_bumps_resume_version;
+DROP TRIGGER IF EXISTS skills_insert_bumps_resume_version;
+DROP TRIGGER IF EXISTS skills_update_bumps_resume_version;
+DROP TRIGGER IF EXISTS skills_delete_bumps_resume_version;
+DROP TRIGGER IF EXISTS experience_insert_bumps_resume_version;
+DROP TRIGGER IF EXISTS experience_update_bumps_resume_version;
+DROP TRIGGER IF EXISTS experience_delete_bumps_resume_version;
+DROP TRIGGER IF EXISTS projects_insert_bumps_resume_version;
+DROP TRIGGER IF EXISTS projects_update_bumps_resume_version;
+DROP TRIGGER IF EXISTS projects_delete_bumps_resume_version;
+DROP TRIGGER IF EXISTS education_insert_bumps_resume_version;
+DROP TRIGGER IF EXISTS education_update_bumps_resume_version;
+DROP TRIGGER IF EXISTS education_delete_bumps_resume_version;
+DROP TRIGGER IF EXISTS links_insert_bumps_resume_version;
+DROP TRIGGER IF EXISTS links_update_bumps_resume_version;
+DROP TRIGGER IF EXISTS links_delete_bumps_resume_version;
+DROP TABLE IF EXISTS resume_version;
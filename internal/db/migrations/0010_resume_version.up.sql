@@ -0,0 +1,98 @@
+-- A counter bumped by every write to the resume tables, whoever makes it,
+-- so the server can poll one row to notice the resume changed.
+CREATE TABLE IF NOT EXISTS resume_version (
+id INTEGER PRIMARY KEY CHECK (id = 1),
+version INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT OR IGNORE INTO resume_version (id, version) VALUES (1, 0);
+
+CREATE TRIGGER IF NOT EXISTS profile_insert_bumps_resume_version AFTER INSERT ON profile
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS profile_update_bumps_resume_version AFTER UPDATE ON profile
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS profile_delete_bumps_resume_version AFTER DELETE ON profile
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS skills_insert_bumps_resume_version AFTER INSERT ON skills
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS skills_update_bumps_resume_version AFTER UPDATE ON skills
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS skills_delete_bumps_resume_version AFTER DELETE ON skills
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS experience_insert_bumps_resume_version AFTER INSERT ON experience
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS experience_update_bumps_resume_version AFTER UPDATE ON experience
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS experience_delete_bumps_resume_version AFTER DELETE ON experience
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS projects_insert_bumps_resume_version AFTER INSERT ON projects
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS projects_update_bumps_resume_version AFTER UPDATE ON projects
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS projects_delete_bumps_resume_version AFTER DELETE ON projects
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS education_insert_bumps_resume_version AFTER INSERT ON education
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS education_update_bumps_resume_version AFTER UPDATE ON education
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS education_delete_bumps_resume_version AFTER DELETE ON education
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS links_insert_bumps_resume_version AFTER INSERT ON links
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS links_update_bumps_resume_version AFTER UPDATE ON links
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS links_delete_bumps_resume_version AFTER DELETE ON links
+BEGIN
+UPDATE resume_version SET version = version + 1;
+END;
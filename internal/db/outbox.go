@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxQueued  OutboxStatus = "queued"
+	OutboxSending OutboxStatus = "sending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxDead    OutboxStatus = "dead"
+)
+
+// OutboxEntry is an email waiting to be delivered by the mail queue. When
+// MessageID is set, delivery progress is mirrored onto that message.
+type OutboxEntry struct {
+	ID            int64
+	MessageID     int64
+	Sender        string
+	Recipients    []string
+	ReplyTo       string
+	Subject       string
+	TextBody      string
+	HTMLBody      string
+	Status        OutboxStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+type OutboxCounts struct {
+	Queued int
+	Sent   int
+	Failed int
+}
+
+func (db *Database) EnqueueMail(ctx context.Context, e *OutboxEntry) error {
+	now := time.Now().UTC()
+	e.Status = OutboxQueued
+	e.CreatedAt = now
+	if e.NextAttemptAt.IsZero() {
+		e.NextAttemptAt = now
+	}
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+		INSERT INTO outbox (message_id, sender, recipients, reply_to, subject, text_body, html_body, status, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, nullID(e.MessageID), e.Sender, strings.Join(e.Recipients, ","), e.ReplyTo, e.Subject, e.TextBody, e.HTMLBody,
+			e.Status, e.NextAttemptAt, e.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue mail: %w", err)
+		}
+		if e.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return setMessageStatus(ctx, tx, e.MessageID, DeliveryQueued, "")
+	})
+}
+
+// ClaimDueMail marks up to limit queued entries whose next attempt is due as
+// sending and returns them.
+func (db *Database) ClaimDueMail(ctx context.Context, now time.Time, limit int) ([]OutboxEntry, error) {
+	var entries []OutboxEntry
+	err := db.inTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+		SELECT id, COALESCE(message_id, 0), sender, recipients, reply_to, subject, text_body, html_body,
+			attempts, last_error, next_attempt_at, created_at
+		FROM outbox
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+		`, OutboxQueued, now.UTC(), limit)
+		if err != nil {
+			return fmt.Errorf("failed to read outbox: %w", err)
+		}
+		for rows.Next() {
+			var (
+				e          OutboxEntry
+				recipients string
+			)
+			if err := rows.Scan(&e.ID, &e.MessageID, &e.Sender, &recipients, &e.ReplyTo, &e.Subject, &e.TextBody, &e.HTMLBody,
+				&e.Attempts, &e.LastError, &e.NextAttemptAt, &e.CreatedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			if recipients != "" {
+				e.Recipients = strings.Split(recipients, ",")
+			}
+			e.Status = OutboxSending
+			entries = append(entries, e)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			if _, err := tx.ExecContext(ctx, `UPDATE outbox SET status = ? WHERE id = ?`, OutboxSending, e.ID); err != nil {
+				return fmt.Errorf("failed to claim outbox entry %d: %w", e.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (db *Database) MarkMailSent(ctx context.Context, e *OutboxEntry) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+		UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = '', sent_at = ? WHERE id = ?
+		`, OutboxSent, time.Now().UTC(), e.ID)
+		if err != nil {
+			return fmt.Errorf("failed to mark mail %d sent: %w", e.ID, err)
+		}
+		return setMessageStatus(ctx, tx, e.MessageID, DeliverySent, "")
+	})
+}
+
+// MarkMailFailed records a failed attempt. The entry is retried at
+// nextAttempt, or moved to the dead letter state when dead is set.
+func (db *Database) MarkMailFailed(ctx context.Context, e *OutboxEntry, nextAttempt time.Time, errMsg string, dead bool) error {
+	status, messageStatus := OutboxQueued, DeliveryQueued
+	if dead {
+		status, messageStatus = OutboxDead, DeliveryFailed
+	}
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+		UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?
+		`, status, errMsg, nextAttempt.UTC(), e.ID)
+		if err != nil {
+			return fmt.Errorf("failed to mark mail %d failed: %w", e.ID, err)
+		}
+		return setMessageStatus(ctx, tx, e.MessageID, messageStatus, errMsg)
+	})
+}
+
+// RequeueInterruptedMail returns entries left in the sending state by a
+// crash or shutdown to the queue.
+func (db *Database) RequeueInterruptedMail(ctx context.Context) (int64, error) {
+	res, err := db.ExecContext(ctx, `UPDATE outbox SET status = ? WHERE status = ?`, OutboxQueued, OutboxSending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue interrupted mail: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (db *Database) OutboxCounts(ctx context.Context) (OutboxCounts, error) {
+	var c OutboxCounts
+	err := db.QueryRowContext(ctx, `
+	SELECT
+		COALESCE(SUM(status IN (?, ?)), 0),
+		COALESCE(SUM(status = ?), 0),
+		COALESCE(SUM(status = ?), 0)
+	FROM outbox
+	`, OutboxQueued, OutboxSending, OutboxSent, OutboxDead).Scan(&c.Queued, &c.Sent, &c.Failed)
+	if err != nil {
+		return c, fmt.Errorf("failed to count outbox: %w", err)
+	}
+	return c, nil
+}
+
+func setMessageStatus(ctx context.Context, tx *sql.Tx, messageID int64, status DeliveryStatus, deliveryErr string) error {
+	if messageID == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE messages SET delivery_status = ?, delivery_error = ? WHERE id = ?`, status, deliveryErr, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to update message %d: %w", messageID, err)
+	}
+	return nil
+}
+
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
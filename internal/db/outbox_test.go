@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func enqueue(t *testing.T, db *Database, e *OutboxEntry) {
+	t.Helper()
+	if err := db.EnqueueMail(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func ids(entries []OutboxEntry) []int64 {
+	var ids []int64
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestClaimDueMail(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	now := time.Now()
+
+	later := &OutboxEntry{Sender: "lair@example.com", Recipients: []string{"a@example.com"}, NextAttemptAt: now.Add(time.Hour)}
+	first := &OutboxEntry{Sender: "lair@example.com", Recipients: []string{"a@example.com", "b@example.com"}, Subject: "First", NextAttemptAt: now.Add(-time.Minute)}
+	second := &OutboxEntry{Sender: "lair@example.com", Recipients: []string{"a@example.com"}, Subject: "Second"}
+	third := &OutboxEntry{Sender: "lair@example.com", Recipients: []string{"a@example.com"}, Subject: "Third"}
+	for _, e := range []*OutboxEntry{later, first, second, third} {
+		enqueue(t, db, e)
+	}
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		limit int
+		want  []int64
+	}{
+		{"oldest due first, up to the limit", now.Add(time.Second), 2, []int64{first.ID, second.ID}},
+		{"claimed entries are not handed out again", now.Add(time.Second), 10, []int64{third.ID}},
+		{"nothing due", now.Add(time.Second), 10, nil},
+		{"future entries once due", now.Add(2 * time.Hour), 10, []int64{later.ID}},
+	}
+	for _, tt := range tests {
+		got, err := db.ClaimDueMail(ctx, tt.now, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(ids(got), tt.want) {
+			t.Errorf("%s: claimed %v, want %v", tt.name, ids(got), tt.want)
+		}
+		for _, e := range got {
+			if e.Status != OutboxSending {
+				t.Errorf("%s: entry %d is %q", tt.name, e.ID, e.Status)
+			}
+		}
+	}
+
+	if c, err := db.OutboxCounts(ctx); err != nil || c != (OutboxCounts{Queued: 4}) {
+		t.Errorf("OutboxCounts() = %+v, %v, want 4 queued", c, err)
+	}
+}
+
+func TestClaimDueMailReadsTheEntry(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	want := OutboxEntry{
+		Sender:     "lair@example.com",
+		Recipients: []string{"a@example.com", "b@example.com"},
+		ReplyTo:    "visitor@example.com",
+		Subject:    "Hello",
+		TextBody:   "Hi.",
+		HTMLBody:   "<p>Hi.</p>",
+	}
+	enqueue(t, db, &want)
+
+	got, err := db.ClaimDueMail(ctx, time.Now().Add(time.Second), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("claimed %d entries", len(got))
+	}
+	e := got[0]
+	if e.ID != want.ID || e.Sender != want.Sender || !slices.Equal(e.Recipients, want.Recipients) || e.ReplyTo != want.ReplyTo ||
+		e.Subject != want.Subject || e.TextBody != want.TextBody || e.HTMLBody != want.HTMLBody || e.Attempts != 0 {
+		t.Errorf("ClaimDueMail() = %+v, want %+v", e, want)
+	}
+}
+
+func TestRequeueInterruptedMail(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	entries := make([]OutboxEntry, 3)
+	for i := range entries {
+		entries[i] = OutboxEntry{Sender: "lair@example.com", Recipients: []string{"a@example.com"}}
+		enqueue(t, db, &entries[i])
+	}
+	claimed, err := db.ClaimDueMail(ctx, time.Now().Add(time.Second), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.MarkMailSent(ctx, &claimed[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the entry still sending when the process stopped is requeued.
+	n, err := db.RequeueInterruptedMail(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("RequeueInterruptedMail() = %d, %v, want 1", n, err)
+	}
+	got, err := db.ClaimDueMail(ctx, time.Now().Add(time.Second), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{claimed[1].ID, entries[2].ID}; !slices.Equal(ids(got), want) {
+		t.Errorf("claimed %v after requeueing, want %v", ids(got), want)
+	}
+}
+
+func TestMarkMail(t *testing.T) {
+	tests := []struct {
+		name        string
+		mark        func(db *Database, e *OutboxEntry) error
+		wantStatus  DeliveryStatus
+		wantError   string
+		wantCounts  OutboxCounts
+		wantClaimed bool
+	}{
+		{
+			name:       "sent",
+			mark:       func(db *Database, e *OutboxEntry) error { return db.MarkMailSent(context.Background(), e) },
+			wantStatus: DeliverySent,
+			wantCounts: OutboxCounts{Sent: 1},
+		},
+		{
+			name: "retried",
+			mark: func(db *Database, e *OutboxEntry) error {
+				return db.MarkMailFailed(context.Background(), e, time.Now(), "timeout", false)
+			},
+			wantStatus:  DeliveryQueued,
+			wantError:   "timeout",
+			wantCounts:  OutboxCounts{Queued: 1},
+			wantClaimed: true,
+		},
+		{
+			name: "retried later",
+			mark: func(db *Database, e *OutboxEntry) error {
+				return db.MarkMailFailed(context.Background(), e, time.Now().Add(time.Hour), "timeout", false)
+			},
+			wantStatus: DeliveryQueued,
+			wantError:  "timeout",
+			wantCounts: OutboxCounts{Queued: 1},
+		},
+		{
+			name: "dead-lettered",
+			mark: func(db *Database, e *OutboxEntry) error {
+				return db.MarkMailFailed(context.Background(), e, time.Now(), "mailbox unavailable", true)
+			},
+			wantStatus: DeliveryFailed,
+			wantError:  "mailbox unavailable",
+			wantCounts: OutboxCounts{Failed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newTestDatabase(t)
+			msg := &Message{Name: "Visitor", Email: "visitor@example.com", Body: "Hi"}
+			if err := db.CreateMessage(ctx, msg); err != nil {
+				t.Fatal(err)
+			}
+			enqueue(t, db, &OutboxEntry{MessageID: msg.ID, Sender: "lair@example.com", Recipients: []string{"a@example.com"}})
+			claimed, err := db.ClaimDueMail(ctx, time.Now().Add(time.Second), 1)
+			if err != nil || len(claimed) != 1 {
+				t.Fatalf("ClaimDueMail() = %v, %v", claimed, err)
+			}
+
+			if err := tt.mark(db, &claimed[0]); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := db.GetMessage(ctx, msg.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.DeliveryStatus != tt.wantStatus || got.DeliveryError != tt.wantError {
+				t.Errorf("message delivery = %q, %q, want %q, %q", got.DeliveryStatus, got.DeliveryError, tt.wantStatus, tt.wantError)
+			}
+			if c, err := db.OutboxCounts(ctx); err != nil || c != tt.wantCounts {
+				t.Errorf("OutboxCounts() = %+v, %v, want %+v", c, err, tt.wantCounts)
+			}
+			again, err := db.ClaimDueMail(ctx, time.Now().Add(time.Second), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (len(again) == 1) != tt.wantClaimed {
+				t.Fatalf("claimed %v again, want %v", ids(again), tt.wantClaimed)
+			}
+			if tt.wantClaimed && again[0].Attempts != 1 {
+				t.Errorf("attempts = %d, want 1", again[0].Attempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HitRateLimit counts one event against each of keys, allowing at most limit
+// events per key in every window. If any key is already at its limit nothing
+// is counted and the time until its window ends is returned; a zero duration
+// means the event is allowed.
+func (db *Database) HitRateLimit(ctx context.Context, keys []string, limit int, window time.Duration) (time.Duration, error) {
+	now := time.Now().UTC()
+	var retryAfter time.Duration
+	err := db.inTx(ctx, func(tx *sql.Tx) error {
+		for _, key := range keys {
+			var (
+				start time.Time
+				count int
+			)
+			err := tx.QueryRowContext(ctx, `SELECT window_start, count FROM rate_limits WHERE key = ?`, key).Scan(&start, &count)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if end := start.Add(window); now.Before(end) && count >= limit {
+				retryAfter = max(retryAfter, end.Sub(now))
+			}
+		}
+		if retryAfter > 0 {
+			return nil
+		}
+		for _, key := range keys {
+			// A window that has ended starts over from this event.
+			_, err := tx.ExecContext(ctx, `
+			INSERT INTO rate_limits (key, window_start, count) VALUES (?, ?, 1)
+			ON CONFLICT(key) DO UPDATE SET
+				count = CASE WHEN window_start <= ? THEN 1 ELSE count + 1 END,
+				window_start = CASE WHEN window_start <= ? THEN excluded.window_start ELSE window_start END
+			`, key, now, now.Add(-window), now.Add(-window))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	return retryAfter, nil
+}
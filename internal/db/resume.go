@@ -0,0 +1,396 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type Profile struct {
+	ID       int64
+	Name     string
+	Headline string
+	Email    string
+	Location string
+	Summary  string
+}
+
+type Skill struct {
+	ID       int64
+	Category string
+	Name     string
+	Position int
+}
+
+type Experience struct {
+	ID          int64
+	Company     string
+	Role        string
+	Location    string
+	StartDate   string
+	EndDate     string
+	Description string
+	Position    int
+}
+
+type Project struct {
+	ID          int64
+	Name        string
+	Description string
+	URL         string
+	Tech        string
+	Position    int
+}
+
+type Education struct {
+	ID          int64
+	Institution string
+	Degree      string
+	StartDate   string
+	EndDate     string
+	Description string
+	Position    int
+}
+
+type Link struct {
+	ID       int64
+	Label    string
+	URL      string
+	Position int
+}
+
+// execer is what the resume writers need of a *Database or a *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Resume groups every resume record in the order it should be displayed.
+type Resume struct {
+	Profile    *Profile
+	Skills     []Skill
+	Experience []Experience
+	Projects   []Project
+	Education  []Education
+	Links      []Link
+}
+
+func (r *Resume) IsEmpty() bool {
+	return r.Profile == nil &&
+		len(r.Skills) == 0 &&
+		len(r.Experience) == 0 &&
+		len(r.Projects) == 0 &&
+		len(r.Education) == 0 &&
+		len(r.Links) == 0
+}
+
+func (db *Database) LoadResume(ctx context.Context) (*Resume, error) {
+	var (
+		r   Resume
+		err error
+	)
+	if r.Profile, err = db.GetProfile(ctx); err != nil {
+		return nil, err
+	}
+	if r.Skills, err = db.ListSkills(ctx); err != nil {
+		return nil, err
+	}
+	if r.Experience, err = db.ListExperience(ctx); err != nil {
+		return nil, err
+	}
+	if r.Projects, err = db.ListProjects(ctx); err != nil {
+		return nil, err
+	}
+	if r.Education, err = db.ListEducation(ctx); err != nil {
+		return nil, err
+	}
+	if r.Links, err = db.ListLinks(ctx); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ResumeVersion returns a number that changes with every write to the
+// resume tables, including writes made by other processes.
+func (db *Database) ResumeVersion(ctx context.Context) (int64, error) {
+	var v int64
+	if err := db.QueryRowContext(ctx, `SELECT version FROM resume_version WHERE id = 1`).Scan(&v); err != nil {
+		return 0, fmt.Errorf("failed to get resume version: %w", err)
+	}
+	return v, nil
+}
+
+// GetProfile returns the resume profile, or nil if none has been saved yet.
+func (db *Database) GetProfile(ctx context.Context) (*Profile, error) {
+	var p Profile
+	err := db.QueryRowContext(ctx, `
+	SELECT id, name, headline, email, location, summary
+	FROM profile ORDER BY id LIMIT 1
+	`).Scan(&p.ID, &p.Name, &p.Headline, &p.Email, &p.Location, &p.Summary)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return &p, nil
+}
+
+// SaveProfile replaces the resume profile.
+func (db *Database) SaveProfile(ctx context.Context, p *Profile) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := saveProfile(ctx, tx, p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveProfile(ctx context.Context, ex execer, p *Profile) error {
+	if _, err := ex.ExecContext(ctx, `DELETE FROM profile`); err != nil {
+		return fmt.Errorf("failed to clear profile: %w", err)
+	}
+	res, err := ex.ExecContext(ctx, `
+	INSERT INTO profile (name, headline, email, location, summary)
+	VALUES (?, ?, ?, ?, ?)
+	`, p.Name, p.Headline, p.Email, p.Location, p.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+	p.ID, err = res.LastInsertId()
+	return err
+}
+
+func (db *Database) ListSkills(ctx context.Context) ([]Skill, error) {
+	rows, err := db.QueryContext(ctx, `
+	SELECT id, category, name, position
+	FROM skills ORDER BY position, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+	defer rows.Close()
+
+	var skills []Skill
+	for rows.Next() {
+		var s Skill
+		if err := rows.Scan(&s.ID, &s.Category, &s.Name, &s.Position); err != nil {
+			return nil, err
+		}
+		skills = append(skills, s)
+	}
+	return skills, rows.Err()
+}
+
+func (db *Database) AddSkill(ctx context.Context, s *Skill) error {
+	return addSkill(ctx, db, s)
+}
+
+func addSkill(ctx context.Context, ex execer, s *Skill) error {
+	res, err := ex.ExecContext(ctx, `
+	INSERT INTO skills (category, name, position) VALUES (?, ?, ?)
+	`, s.Category, s.Name, s.Position)
+	if err != nil {
+		return fmt.Errorf("failed to add skill: %w", err)
+	}
+	s.ID, err = res.LastInsertId()
+	return err
+}
+
+func (db *Database) ListExperience(ctx context.Context) ([]Experience, error) {
+	rows, err := db.QueryContext(ctx, `
+	SELECT id, company, role, location, start_date, end_date, description, position
+	FROM experience ORDER BY position, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experience: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Experience
+	for rows.Next() {
+		var e Experience
+		if err := rows.Scan(&e.ID, &e.Company, &e.Role, &e.Location, &e.StartDate, &e.EndDate, &e.Description, &e.Position); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (db *Database) AddExperience(ctx context.Context, e *Experience) error {
+	return addExperience(ctx, db, e)
+}
+
+func addExperience(ctx context.Context, ex execer, e *Experience) error {
+	res, err := ex.ExecContext(ctx, `
+	INSERT INTO experience (company, role, location, start_date, end_date, description, position)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, e.Company, e.Role, e.Location, e.StartDate, e.EndDate, e.Description, e.Position)
+	if err != nil {
+		return fmt.Errorf("failed to add experience: %w", err)
+	}
+	e.ID, err = res.LastInsertId()
+	return err
+}
+
+func (db *Database) ListProjects(ctx context.Context) ([]Project, error) {
+	rows, err := db.QueryContext(ctx, `
+	SELECT id, name, description, url, tech, position
+	FROM projects ORDER BY position, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		var p Project
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.URL, &p.Tech, &p.Position); err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
+func (db *Database) AddProject(ctx context.Context, p *Project) error {
+	return addProject(ctx, db, p)
+}
+
+func addProject(ctx context.Context, ex execer, p *Project) error {
+	res, err := ex.ExecContext(ctx, `
+	INSERT INTO projects (name, description, url, tech, position)
+	VALUES (?, ?, ?, ?, ?)
+	`, p.Name, p.Description, p.URL, p.Tech, p.Position)
+	if err != nil {
+		return fmt.Errorf("failed to add project: %w", err)
+	}
+	p.ID, err = res.LastInsertId()
+	return err
+}
+
+func (db *Database) ListEducation(ctx context.Context) ([]Education, error) {
+	rows, err := db.QueryContext(ctx, `
+	SELECT id, institution, degree, start_date, end_date, description, position
+	FROM education ORDER BY position, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list education: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Education
+	for rows.Next() {
+		var e Education
+		if err := rows.Scan(&e.ID, &e.Institution, &e.Degree, &e.StartDate, &e.EndDate, &e.Description, &e.Position); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (db *Database) AddEducation(ctx context.Context, e *Education) error {
+	return addEducation(ctx, db, e)
+}
+
+func addEducation(ctx context.Context, ex execer, e *Education) error {
+	res, err := ex.ExecContext(ctx, `
+	INSERT INTO education (institution, degree, start_date, end_date, description, position)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`, e.Institution, e.Degree, e.StartDate, e.EndDate, e.Description, e.Position)
+	if err != nil {
+		return fmt.Errorf("failed to add education: %w", err)
+	}
+	e.ID, err = res.LastInsertId()
+	return err
+}
+
+func (db *Database) ListLinks(ctx context.Context) ([]Link, error) {
+	rows, err := db.QueryContext(ctx, `
+	SELECT id, label, url, position
+	FROM links ORDER BY position, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []Link
+	for rows.Next() {
+		var l Link
+		if err := rows.Scan(&l.ID, &l.Label, &l.URL, &l.Position); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+func (db *Database) AddLink(ctx context.Context, l *Link) error {
+	return addLink(ctx, db, l)
+}
+
+func addLink(ctx context.Context, ex execer, l *Link) error {
+	res, err := ex.ExecContext(ctx, `
+	INSERT INTO links (label, url, position) VALUES (?, ?, ?)
+	`, l.Label, l.URL, l.Position)
+	if err != nil {
+		return fmt.Errorf("failed to add link: %w", err)
+	}
+	l.ID, err = res.LastInsertId()
+	return err
+}
+
+// ImportResume replaces every resume record with those in r, all at once,
+// so visitors never see half a resume. An empty r clears the resume.
+func (db *Database) ImportResume(ctx context.Context, r *Resume) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"profile", "skills", "experience", "projects", "education", "links"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+	}
+	if r.Profile != nil {
+		if err := saveProfile(ctx, tx, r.Profile); err != nil {
+			return err
+		}
+	}
+	for i := range r.Skills {
+		if err := addSkill(ctx, tx, &r.Skills[i]); err != nil {
+			return err
+		}
+	}
+	for i := range r.Experience {
+		if err := addExperience(ctx, tx, &r.Experience[i]); err != nil {
+			return err
+		}
+	}
+	for i := range r.Projects {
+		if err := addProject(ctx, tx, &r.Projects[i]); err != nil {
+			return err
+		}
+	}
+	for i := range r.Education {
+		if err := addEducation(ctx, tx, &r.Education[i]); err != nil {
+			return err
+		}
+	}
+	for i := range r.Links {
+		if err := addLink(ctx, tx, &r.Links[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	db, err := InitDatabase(filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestResumeIsEmpty(t *testing.T) {
+	tests := []struct {
+		name   string
+		resume Resume
+		want   bool
+	}{
+		{"nothing", Resume{}, true},
+		{"profile", Resume{Profile: &Profile{Name: "Dragon"}}, false},
+		{"skill", Resume{Skills: []Skill{{Name: "Go"}}}, false},
+		{"experience", Resume{Experience: []Experience{{Company: "Lair", Role: "Keeper"}}}, false},
+		{"project", Resume{Projects: []Project{{Name: "DragonTUI"}}}, false},
+		{"education", Resume{Education: []Education{{Institution: "School"}}}, false},
+		{"link", Resume{Links: []Link{{Label: "Site", URL: "https://example.com"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resume.IsEmpty(); got != tt.want {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadResume(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+
+	r, err := db.LoadResume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsEmpty() {
+		t.Fatalf("new database has a resume: %+v", r)
+	}
+
+	if err := db.SaveProfile(ctx, &Profile{Name: "Old"}); err != nil {
+		t.Fatal(err)
+	}
+	profile := &Profile{Name: "Dragon", Headline: "Keeper", Email: "dragon@example.com", Location: "Lair", Summary: "Hoards."}
+	if err := db.SaveProfile(ctx, profile); err != nil {
+		t.Fatal(err)
+	}
+	// Records come back by position, then in the order they were added.
+	skills := []Skill{{Category: "Languages", Name: "Go", Position: 1}, {Category: "Languages", Name: "C", Position: 0}, {Name: "Fire", Position: 1}}
+	for i := range skills {
+		if err := db.AddSkill(ctx, &skills[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	job := Experience{Company: "Lair", Role: "Keeper", StartDate: "2020", Description: "Guards gold."}
+	if err := db.AddExperience(ctx, &job); err != nil {
+		t.Fatal(err)
+	}
+	project := Project{Name: "DragonTUI", URL: "https://example.com", Tech: "Go"}
+	if err := db.AddProject(ctx, &project); err != nil {
+		t.Fatal(err)
+	}
+	school := Education{Institution: "School", Degree: "Flight", StartDate: "2010", EndDate: "2014"}
+	if err := db.AddEducation(ctx, &school); err != nil {
+		t.Fatal(err)
+	}
+	link := Link{Label: "Site", URL: "https://example.com"}
+	if err := db.AddLink(ctx, &link); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := db.LoadResume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Resume{
+		Profile:    profile,
+		Skills:     []Skill{skills[1], skills[0], skills[2]},
+		Experience: []Experience{job},
+		Projects:   []Project{project},
+		Education:  []Education{school},
+		Links:      []Link{link},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadResume() = %+v, want %+v", got, want)
+	}
+}
+
+func TestImportResume(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+	if err := db.AddSkill(ctx, &Skill{Name: "Stale"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Resume{
+		Profile:    &Profile{Name: "Dragon"},
+		Skills:     []Skill{{Name: "Go"}, {Name: "Fire", Position: 1}},
+		Experience: []Experience{{Company: "Lair", Role: "Keeper"}},
+		Projects:   []Project{{Name: "DragonTUI"}},
+		Education:  []Education{{Institution: "School"}},
+		Links:      []Link{{Label: "Site", URL: "https://example.com"}},
+	}
+	if err := db.ImportResume(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.LoadResume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("LoadResume() = %+v, want the imported %+v", got, r)
+	}
+
+	if err := db.ImportResume(ctx, &Resume{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := db.LoadResume(ctx); err != nil || !got.IsEmpty() {
+		t.Errorf("after importing nothing LoadResume() = %+v, %v", got, err)
+	}
+}
+
+func TestResumeVersionChangesWithEveryWrite(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatabase(t)
+
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{"save profile", func() error { return db.SaveProfile(ctx, &Profile{Name: "Dragon"}) }},
+		{"add skill", func() error { return db.AddSkill(ctx, &Skill{Name: "Go"}) }},
+		{"add link", func() error { return db.AddLink(ctx, &Link{Label: "Site", URL: "https://example.com"}) }},
+		{"update by hand", func() error {
+			_, err := db.ExecContext(ctx, `UPDATE skills SET name = 'Fire'`)
+			return err
+		}},
+		{"import", func() error { return db.ImportResume(ctx, &Resume{Projects: []Project{{Name: "DragonTUI"}}}) }},
+		{"clear", func() error { return db.ImportResume(ctx, &Resume{}) }},
+	}
+	last, err := db.ResumeVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range writes {
+		if err := w.write(); err != nil {
+			t.Fatalf("%s: %v", w.name, err)
+		}
+		v, err := db.ResumeVersion(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == last {
+			t.Errorf("%s left the version at %d", w.name, v)
+		}
+		last = v
+	}
+}
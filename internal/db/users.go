@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// User is a visitor known by the public key they connect with.
+type User struct {
+	ID             int64
+	Username       string
+	KeyFingerprint string
+	// Name is how the visitor introduced themselves, empty until they do.
+	Name       string
+	Visits     int
+	LastSeenAt time.Time
+	CreatedAt  time.Time
+}
+
+var ErrUserNotFound = errors.New("user not found")
+
+const userColumns = `id, username, COALESCE(key_fingerprint, ''), name, visits, last_seen_at, created_at`
+
+func scanUser(row interface{ Scan(...any) error }) (User, error) {
+	var (
+		u        User
+		lastSeen sql.NullTime
+	)
+	err := row.Scan(&u.ID, &u.Username, &u.KeyFingerprint, &u.Name, &u.Visits, &lastSeen, &u.CreatedAt)
+	u.LastSeenAt = lastSeen.Time
+	return u, err
+}
+
+// RecordVisit finds the user with the given key fingerprint, creating them on
+// their first visit, and counts the visit. The returned user reflects the
+// visit, so Visits > 1 means they have been here before.
+func (db *Database) RecordVisit(ctx context.Context, fingerprint, username string) (*User, error) {
+	if fingerprint == "" {
+		return nil, errors.New("record visit: a key fingerprint is required")
+	}
+	var u User
+	err := db.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		_, err := tx.ExecContext(ctx, `
+		INSERT INTO users (username, key_fingerprint, visits, last_seen_at, created_at)
+		VALUES (?, ?, 1, ?, ?)
+		ON CONFLICT(key_fingerprint) DO UPDATE SET
+			username = excluded.username,
+			visits = visits + 1,
+			last_seen_at = excluded.last_seen_at
+		`, username, fingerprint, now, now)
+		if err != nil {
+			return err
+		}
+		u, err = scanUser(tx.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE key_fingerprint = ?`, fingerprint))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record visit: %w", err)
+	}
+	return &u, nil
+}
+
+func (db *Database) GetUserByFingerprint(ctx context.Context, fingerprint string) (*User, error) {
+	u, err := scanUser(db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE key_fingerprint = ?`, fingerprint))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &u, nil
+}
+
+// SetUserName records the name a visitor goes by.
+func (db *Database) SetUserName(ctx context.Context, id int64, name string) error {
+	res, err := db.ExecContext(ctx, `UPDATE users SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package health serves liveness and readiness probes over HTTP. /healthz
+// answers as long as the process is up; /readyz runs every registered check
+// and fails if any of them does.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// checkTimeout bounds each readiness check, so one slow dependency can't hang
+// the probe.
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency is ready. Its details, such as
+// connection counts, are included in the response whether or not it fails.
+type Check func(ctx context.Context) (details map[string]string, err error)
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Server runs readiness checks in the order they were added.
+type Server struct {
+	checks []namedCheck
+}
+
+func New() *Server {
+	return &Server{}
+}
+
+// Add registers check under name.
+func (s *Server) Add(name string, check Check) {
+	s.checks = append(s.checks, namedCheck{name: name, check: check})
+}
+
+type checkResult struct {
+	Status  string            `json:"status"`
+	Error   string            `json:"error,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+type report struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+// ready runs every check and reports whether they all passed.
+func (s *Server) ready(ctx context.Context) (bool, map[string]checkResult) {
+	ready := true
+	results := make(map[string]checkResult, len(s.checks))
+	for _, c := range s.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		details, err := c.check(checkCtx)
+		cancel()
+		result := checkResult{Status: "ok", Details: details}
+		if err != nil {
+			ready = false
+			result.Status, result.Error = "fail", err.Error()
+		}
+		results[c.name] = result
+	}
+	return ready, results
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, report{Status: "ok"})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		ready, results := s.ready(r.Context())
+		if !ready {
+			writeJSON(w, http.StatusServiceUnavailable, report{Status: "unavailable", Checks: results})
+			return
+		}
+		writeJSON(w, http.StatusOK, report{Status: "ok", Checks: results})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("Could not write health response", "err", err)
+	}
+}
+
+// Serve answers probes on addr until ctx is done.
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: s.Handler(), ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("Serving health checks", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
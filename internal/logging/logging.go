@@ -0,0 +1,49 @@
+// Package logging sets up the server's structured logger.
+package logging
+
+import (
+	"fmt"
+	"io"
+	stdlog "log"
+	"os"
+	"time"
+
+	"DragonTUI/internal/config"
+
+	"github.com/charmbracelet/log"
+)
+
+// Setup builds the logger cfg describes and makes it the default for
+// charmbracelet/log. The standard library's log package is pointed at it too,
+// so lines from dependencies end up in the same place and format. Call the
+// returned func to close the log file.
+func Setup(cfg config.LogConfig) (*log.Logger, func() error, error) {
+	level, err := log.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var w io.Writer = os.Stderr
+	closeFn := func() error { return nil }
+	if cfg.File != "" {
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		w, closeFn = f, f.Close
+	}
+
+	opts := log.Options{Level: level, ReportTimestamp: true}
+	switch cfg.Format {
+	case config.LogJSON:
+		opts.Formatter, opts.TimeFormat = log.JSONFormatter, time.RFC3339
+	case config.LogLogfmt:
+		opts.Formatter, opts.TimeFormat = log.LogfmtFormatter, time.RFC3339
+	}
+
+	logger := log.NewWithOptions(w, opts)
+	log.SetDefault(logger)
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(logger.StandardLog(log.StandardLogOptions{ForceLevel: log.InfoLevel}).Writer())
+	return logger, closeFn, nil
+}
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Message is an outgoing email. Empty From and To fields are filled in with
+// the defaults from Config.
+type Message struct {
+	From    string
+	To      []string
+	ReplyTo string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers a Message through some backend.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+type Backend string
+
+const (
+	BackendResend  Backend = "resend"
+	BackendSMTP    Backend = "smtp"
+	BackendMaildir Backend = "maildir"
+)
+
+type Config struct {
+	Backend Backend
+	From    string
+	To      []string
+
+	ResendAPIKey string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// SMTPStartTLS requires the server to support STARTTLS before any
+	// credentials or mail are sent.
+	SMTPStartTLS bool
+
+	MaildirPath string
+}
+
+// New builds the Mailer selected by cfg.Backend.
+func New(cfg Config) (Mailer, error) {
+	if cfg.From == "" {
+		return nil, errors.New("mail: sender address is required")
+	}
+	if len(cfg.To) == 0 {
+		return nil, errors.New("mail: at least one recipient is required")
+	}
+
+	var (
+		backend Mailer
+		err     error
+	)
+	switch cfg.Backend {
+	case BackendResend, "":
+		backend, err = NewResendMailer(cfg.ResendAPIKey)
+	case BackendSMTP:
+		backend, err = NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPStartTLS)
+	case BackendMaildir:
+		backend, err = NewMaildirMailer(cfg.MaildirPath)
+	default:
+		err = fmt.Errorf("mail: unknown backend %q", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &defaultsMailer{next: backend, from: cfg.From, to: cfg.To}, nil
+}
+
+type defaultsMailer struct {
+	next Mailer
+	from string
+	to   []string
+}
+
+func (m *defaultsMailer) Send(ctx context.Context, msg *Message) error {
+	out := *msg
+	if out.From == "" {
+		out.From = m.from
+	}
+	if len(out.To) == 0 {
+		out.To = m.to
+	}
+	return m.next.Send(ctx, &out)
+}
+
+// ParseAddressList splits a comma separated list of addresses.
+func ParseAddressList(s string) []string {
+	var addrs []string
+	for _, a := range strings.Split(s, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// MaildirMailer writes every message into a local Maildir instead of sending
+// it, which is handy during development. Any mail client can open the
+// directory.
+type MaildirMailer struct {
+	Dir string
+	seq atomic.Uint64
+}
+
+func NewMaildirMailer(dir string) (*MaildirMailer, error) {
+	if dir == "" {
+		return nil, errors.New("mail: maildir path is required")
+	}
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, fmt.Errorf("mail: %w", err)
+		}
+	}
+	return &MaildirMailer{Dir: dir}, nil
+}
+
+func (m *MaildirMailer) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	host, _ := os.Hostname()
+	name := fmt.Sprintf("%d.%d_%d.%s", time.Now().Unix(), os.Getpid(), m.seq.Add(1), host)
+
+	// Deliver into tmp/ first and rename so readers never see a partial file.
+	tmp := filepath.Join(m.Dir, "tmp", name)
+	if err := os.WriteFile(tmp, msg.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(m.Dir, "new", name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("mail: %w", err)
+	}
+	return nil
+}
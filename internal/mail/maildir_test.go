@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"context"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaildirMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Maildir")
+	m, err := NewMaildirMailer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subjects := []string{"First", "Second"}
+	for _, s := range subjects {
+		if err := m.Send(context.Background(), &Message{From: "lair@example.com", To: []string{"a@example.com"}, Subject: s, Text: "Hi."}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for sub, want := range map[string]int{"tmp": 0, "cur": 0, "new": len(subjects)} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != want {
+			t.Errorf("%s holds %d files, want %d", sub, len(entries), want)
+		}
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "new"))
+	var got []string
+	for _, e := range entries {
+		f, err := os.Open(filepath.Join(dir, "new", e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := mail.ReadMessage(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", e.Name(), err)
+		}
+		got = append(got, msg.Header.Get("Subject"))
+	}
+	if strings.Join(got, " ") != strings.Join(subjects, " ") {
+		t.Errorf("subjects = %q, want %q", got, subjects)
+	}
+}
+
+func TestMaildirMailerErrors(t *testing.T) {
+	if _, err := NewMaildirMailer(""); err == nil {
+		t.Error("NewMaildirMailer(\"\") succeeded")
+	}
+
+	m, err := NewMaildirMailer(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Send(ctx, &Message{}); err != context.Canceled {
+		t.Errorf("Send() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+}
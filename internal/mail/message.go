@@ -0,0 +1,80 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Bytes renders msg as an RFC 5322 message. When both Text and HTML are set
+// they are sent as multipart/alternative.
+func (msg *Message) Bytes() []byte {
+	var b bytes.Buffer
+	header := func(k, v string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	header("From", addressHeader(msg.From))
+	header("To", addressHeader(msg.To...))
+	if msg.ReplyTo != "" {
+		header("Reply-To", addressHeader(msg.ReplyTo))
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	switch {
+	case msg.Text != "" && msg.HTML != "":
+		boundary := randomBoundary()
+		header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+		b.WriteString("\r\n")
+		fmt.Fprintf(&b, "--%s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n", boundary, crlf(msg.Text))
+		fmt.Fprintf(&b, "--%s\r\nContent-Type: text/html; charset=utf-8\r\n\r\n%s\r\n", boundary, crlf(msg.HTML))
+		fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	case msg.HTML != "":
+		header("Content-Type", "text/html; charset=utf-8")
+		b.WriteString("\r\n" + crlf(msg.HTML) + "\r\n")
+	default:
+		header("Content-Type", "text/plain; charset=utf-8")
+		b.WriteString("\r\n" + crlf(msg.Text) + "\r\n")
+	}
+	return b.Bytes()
+}
+
+// addressHeader formats addrs for an address header, encoding non-ASCII
+// names. An address that doesn't parse is kept as given, less any line
+// breaks that would start a header of its own.
+func addressHeader(addrs ...string) string {
+	out := make([]string, len(addrs))
+	for i, s := range addrs {
+		if a, err := mail.ParseAddress(s); err == nil {
+			out[i] = a.String()
+		} else {
+			out[i] = strings.NewReplacer("\r", "", "\n", "").Replace(s)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func crlf(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")
+}
+
+func randomBoundary() string {
+	var buf [16]byte
+	rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// envelopeAddress extracts the bare address from "Name <addr>" forms.
+func envelopeAddress(s string) (string, error) {
+	a, err := mail.ParseAddress(s)
+	if err != nil {
+		return "", fmt.Errorf("mail: invalid address %q: %w", s, err)
+	}
+	return a.Address, nil
+}
@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// parse reads msg.Bytes back the way a mail client would.
+func parse(t *testing.T, msg *Message) *mail.Message {
+	t.Helper()
+	raw := msg.Bytes()
+	m, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("%v in:\n%s", err, raw)
+	}
+	return m
+}
+
+func TestMessageHeaders(t *testing.T) {
+	dec := new(mime.WordDecoder)
+	tests := []struct {
+		name string
+		msg  Message
+		want map[string]string
+	}{
+		{
+			name: "plain",
+			msg:  Message{From: "lair@example.com", To: []string{"a@example.com", "b@example.com"}, Subject: "Hello"},
+			want: map[string]string{
+				"From":    "<lair@example.com>",
+				"To":      "<a@example.com>, <b@example.com>",
+				"Subject": "Hello",
+			},
+		},
+		{
+			name: "non-ASCII names and subject",
+			msg:  Message{From: "Drachenhöhle <lair@example.com>", To: []string{"Zoë <zoe@example.com>"}, ReplyTo: "Renée <renee@example.com>", Subject: "Grüße"},
+			want: map[string]string{
+				"From":     "Drachenhöhle <lair@example.com>",
+				"To":       "Zoë <zoe@example.com>",
+				"Reply-To": "Renée <renee@example.com>",
+				"Subject":  "Grüße",
+			},
+		},
+		{
+			name: "line breaks cannot add headers",
+			msg:  Message{From: "lair@example.com", To: []string{"a@example.com"}, ReplyTo: "x@example.com\r\nBcc: all@example.com", Subject: "Hi\r\nBcc: all@example.com"},
+			want: map[string]string{
+				"Reply-To": "x@example.comBcc: all@example.com",
+				"Subject":  "Hi\r\nBcc: all@example.com",
+				"Bcc":      "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := parse(t, &tt.msg)
+			for k, want := range tt.want {
+				raw := m.Header.Get(k)
+				if !isASCII(raw) {
+					t.Errorf("%s is not ASCII: %q", k, raw)
+				}
+				got, err := dec.DecodeHeader(raw)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("%s = %q, want %q", k, got, want)
+				}
+			}
+			if m.Header.Get("Date") == "" || m.Header.Get("MIME-Version") != "1.0" {
+				t.Errorf("missing Date or MIME-Version: %v", m.Header)
+			}
+		})
+	}
+}
+
+func TestMessageBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      Message
+		wantType string
+		// parts are the bodies by content type, one for single-part messages.
+		parts map[string]string
+	}{
+		{
+			name:     "text",
+			msg:      Message{Text: "line one\nline two"},
+			wantType: "text/plain",
+			parts:    map[string]string{"text/plain": "line one\r\nline two\r\n"},
+		},
+		{
+			name:     "html",
+			msg:      Message{HTML: "<p>hi</p>"},
+			wantType: "text/html",
+			parts:    map[string]string{"text/html": "<p>hi</p>\r\n"},
+		},
+		{
+			name:     "both",
+			msg:      Message{Text: "hi\r\nthere", HTML: "<p>hi</p>"},
+			wantType: "multipart/alternative",
+			parts:    map[string]string{"text/plain": "hi\r\nthere", "text/html": "<p>hi</p>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.msg.From, tt.msg.To = "lair@example.com", []string{"a@example.com"}
+			m := parse(t, &tt.msg)
+			mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mediaType != tt.wantType {
+				t.Fatalf("Content-Type = %q, want %q", mediaType, tt.wantType)
+			}
+
+			got := make(map[string]string)
+			if mediaType != "multipart/alternative" {
+				body, _ := io.ReadAll(m.Body)
+				got[mediaType] = string(body)
+			} else {
+				r := multipart.NewReader(m.Body, params["boundary"])
+				for {
+					p, err := r.NextPart()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					partType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+					body, _ := io.ReadAll(p)
+					got[partType] = string(body)
+				}
+			}
+			for k, want := range tt.parts {
+				if got[k] != want {
+					t.Errorf("%s body = %q, want %q", k, got[k], want)
+				}
+			}
+			if len(got) != len(tt.parts) {
+				t.Errorf("got parts %v, want %v", got, tt.parts)
+			}
+		})
+	}
+}
+
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
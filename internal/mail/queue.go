@@ -0,0 +1,191 @@
+package mail
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"DragonTUI/internal/db"
+
+	"github.com/charmbracelet/log"
+)
+
+// OutboxStore is the durable storage behind a Queue.
+type OutboxStore interface {
+	EnqueueMail(ctx context.Context, e *db.OutboxEntry) error
+	ClaimDueMail(ctx context.Context, now time.Time, limit int) ([]db.OutboxEntry, error)
+	MarkMailSent(ctx context.Context, e *db.OutboxEntry) error
+	MarkMailFailed(ctx context.Context, e *db.OutboxEntry, nextAttempt time.Time, errMsg string, dead bool) error
+	RequeueInterruptedMail(ctx context.Context) (int64, error)
+	OutboxCounts(ctx context.Context) (db.OutboxCounts, error)
+}
+
+type QueueOptions struct {
+	// MaxAttempts is how many sends are tried before an entry is
+	// dead-lettered.
+	MaxAttempts int
+	// BaseDelay is the wait after the first failure; it doubles with every
+	// further failure up to MaxDelay.
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+	SendTimeout  time.Duration
+	// OnDelivery, if set, is told the outcome of every delivery attempt:
+	// sent, retry or dead.
+	OnDelivery func(outcome string)
+}
+
+func DefaultQueueOptions() QueueOptions {
+	return QueueOptions{
+		MaxAttempts:  8,
+		BaseDelay:    30 * time.Second,
+		MaxDelay:     time.Hour,
+		PollInterval: 15 * time.Second,
+		BatchSize:    10,
+		SendTimeout:  30 * time.Second,
+	}
+}
+
+// Queue delivers mail from a SQLite outbox in the background, so a message
+// survives both provider outages and the visitor disconnecting.
+type Queue struct {
+	store  OutboxStore
+	mailer Mailer
+	opts   QueueOptions
+	wake   chan struct{}
+}
+
+func NewQueue(store OutboxStore, mailer Mailer, opts QueueOptions) *Queue {
+	return &Queue{
+		store:  store,
+		mailer: mailer,
+		opts:   opts,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Enqueue stores msg for delivery. messageID links the entry to a contact
+// message whose delivery status should follow it, or is 0.
+func (q *Queue) Enqueue(ctx context.Context, msg *Message, messageID int64) error {
+	err := q.store.EnqueueMail(ctx, &db.OutboxEntry{
+		MessageID:  messageID,
+		Sender:     msg.From,
+		Recipients: msg.To,
+		ReplyTo:    msg.ReplyTo,
+		Subject:    msg.Subject,
+		TextBody:   msg.Text,
+		HTMLBody:   msg.HTML,
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *Queue) Stats(ctx context.Context) (db.OutboxCounts, error) {
+	return q.store.OutboxCounts(ctx)
+}
+
+// Run delivers due mail until ctx is cancelled.
+func (q *Queue) Run(ctx context.Context) {
+	if n, err := q.store.RequeueInterruptedMail(ctx); err != nil {
+		log.Error("Could not requeue interrupted mail", "err", err)
+	} else if n > 0 {
+		log.Info("Requeued interrupted mail", "count", n)
+	}
+
+	ticker := time.NewTicker(q.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		q.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+func (q *Queue) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		entries, err := q.store.ClaimDueMail(ctx, time.Now(), q.opts.BatchSize)
+		if err != nil {
+			log.Error("Could not claim due mail", "err", err)
+			return
+		}
+		if len(entries) == 0 {
+			return
+		}
+		for i := range entries {
+			q.deliver(ctx, &entries[i])
+		}
+
+		if counts, err := q.store.OutboxCounts(ctx); err == nil {
+			log.Info("Mail queue", "queued", counts.Queued, "sent", counts.Sent, "failed", counts.Failed)
+		}
+	}
+}
+
+func (q *Queue) deliver(ctx context.Context, e *db.OutboxEntry) {
+	sendCtx, cancel := context.WithTimeout(ctx, q.opts.SendTimeout)
+	err := q.mailer.Send(sendCtx, &Message{
+		From:    e.Sender,
+		To:      e.Recipients,
+		ReplyTo: e.ReplyTo,
+		Subject: e.Subject,
+		Text:    e.TextBody,
+		HTML:    e.HTMLBody,
+	})
+	cancel()
+
+	// Record the outcome even if we are shutting down, otherwise the entry
+	// would be requeued and possibly sent twice.
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err == nil {
+		q.report("sent")
+		if err := q.store.MarkMailSent(recordCtx, e); err != nil {
+			log.Error("Could not mark mail sent", "mail", e.ID, "err", err)
+		}
+		return
+	}
+
+	attempts := e.Attempts + 1
+	dead := attempts >= q.opts.MaxAttempts
+	if dead {
+		q.report("dead")
+	} else {
+		q.report("retry")
+	}
+	next := time.Now().Add(q.backoff(attempts))
+	if dead {
+		log.Warn("Giving up on mail", "mail", e.ID, "attempts", attempts, "err", err)
+	}
+	if err := q.store.MarkMailFailed(recordCtx, e, next, err.Error(), dead); err != nil {
+		log.Error("Could not mark mail failed", "mail", e.ID, "err", err)
+	}
+}
+
+func (q *Queue) report(outcome string) {
+	if q.opts.OnDelivery != nil {
+		q.opts.OnDelivery(outcome)
+	}
+}
+
+// backoff returns the delay before retry number attempts, with up to 10%
+// jitter so a provider outage doesn't produce a thundering herd.
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := q.opts.BaseDelay
+	for i := 1; i < attempts && d < q.opts.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, q.opts.MaxDelay)
+	return d + time.Duration(rand.Int64N(int64(d)/10+1))
+}
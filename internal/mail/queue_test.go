@@ -0,0 +1,207 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"DragonTUI/internal/db"
+)
+
+func TestQueueBackoff(t *testing.T) {
+	q := NewQueue(nil, nil, QueueOptions{BaseDelay: time.Second, MaxDelay: 10 * time.Second})
+	tests := []struct {
+		attempts int
+		base     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		// Jitter adds up to a tenth of the delay.
+		lo, hi := tt.base, tt.base+tt.base/10
+		jittered := false
+		for range 100 {
+			d := q.backoff(tt.attempts)
+			if d < lo || d > hi {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempts, d, lo, hi)
+			}
+			jittered = jittered || d != lo
+		}
+		if !jittered {
+			t.Errorf("backoff(%d) was never jittered", tt.attempts)
+		}
+	}
+}
+
+// fakeOutbox is an OutboxStore that hands out due entries once and records
+// what the queue reports back.
+type fakeOutbox struct {
+	mu       sync.Mutex
+	due      []db.OutboxEntry
+	requeued bool
+	sent     []int64
+	failed   []failure
+}
+
+type failure struct {
+	id          int64
+	nextAttempt time.Time
+	err         string
+	dead        bool
+}
+
+func (s *fakeOutbox) EnqueueMail(ctx context.Context, e *db.OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.ID = int64(len(s.due) + 1)
+	s.due = append(s.due, *e)
+	return nil
+}
+
+func (s *fakeOutbox) ClaimDueMail(ctx context.Context, now time.Time, limit int) ([]db.OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := min(limit, len(s.due))
+	claimed := s.due[:n]
+	s.due = s.due[n:]
+	return claimed, nil
+}
+
+func (s *fakeOutbox) MarkMailSent(ctx context.Context, e *db.OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, e.ID)
+	return nil
+}
+
+func (s *fakeOutbox) MarkMailFailed(ctx context.Context, e *db.OutboxEntry, nextAttempt time.Time, errMsg string, dead bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = append(s.failed, failure{e.ID, nextAttempt, errMsg, dead})
+	return nil
+}
+
+func (s *fakeOutbox) RequeueInterruptedMail(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requeued = true
+	return 0, nil
+}
+
+func (s *fakeOutbox) OutboxCounts(ctx context.Context) (db.OutboxCounts, error) {
+	return db.OutboxCounts{}, nil
+}
+
+type mailerFunc func(ctx context.Context, msg *Message) error
+
+func (f mailerFunc) Send(ctx context.Context, msg *Message) error { return f(ctx, msg) }
+
+func TestQueueDeliver(t *testing.T) {
+	errDown := errors.New("provider down")
+	tests := []struct {
+		name     string
+		attempts int
+		sendErr  error
+		want     string
+		wantDead bool
+	}{
+		{name: "sent", attempts: 0, want: "sent"},
+		{name: "first failure is retried", attempts: 0, sendErr: errDown, want: "retry"},
+		{name: "retried until the last attempt", attempts: 2, sendErr: errDown, want: "retry"},
+		{name: "dead-lettered after max attempts", attempts: 3, sendErr: errDown, want: "dead", wantDead: true},
+		{name: "sent on the last attempt", attempts: 3, want: "sent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeOutbox{}
+			var outcomes []string
+			opts := QueueOptions{
+				MaxAttempts: 4,
+				BaseDelay:   time.Minute,
+				MaxDelay:    time.Hour,
+				SendTimeout: time.Second,
+				OnDelivery:  func(outcome string) { outcomes = append(outcomes, outcome) },
+			}
+			var got *Message
+			q := NewQueue(store, mailerFunc(func(ctx context.Context, msg *Message) error {
+				got = msg
+				return tt.sendErr
+			}), opts)
+
+			start := time.Now()
+			q.deliver(context.Background(), &db.OutboxEntry{
+				ID: 7, Sender: "lair@example.com", Recipients: []string{"a@example.com"}, Subject: "Hi", Attempts: tt.attempts,
+			})
+
+			if got == nil || got.Subject != "Hi" || got.From != "lair@example.com" {
+				t.Errorf("sent %+v", got)
+			}
+			if len(outcomes) != 1 || outcomes[0] != tt.want {
+				t.Errorf("outcomes = %q, want %q", outcomes, tt.want)
+			}
+			if tt.sendErr == nil {
+				if len(store.sent) != 1 || len(store.failed) != 0 {
+					t.Errorf("sent %v, failed %v", store.sent, store.failed)
+				}
+				return
+			}
+			if len(store.failed) != 1 {
+				t.Fatalf("failed %v", store.failed)
+			}
+			f := store.failed[0]
+			if f.dead != tt.wantDead || f.err != errDown.Error() {
+				t.Errorf("MarkMailFailed(dead %v, %q), want dead %v", f.dead, f.err, tt.wantDead)
+			}
+			if wait := f.nextAttempt.Sub(start); wait < q.backoff(tt.attempts+1)*10/11 {
+				t.Errorf("next attempt in %s, sooner than the backoff", wait)
+			}
+		})
+	}
+}
+
+func TestQueueRun(t *testing.T) {
+	store := &fakeOutbox{}
+	delivered := make(chan string, 3)
+	q := NewQueue(store, mailerFunc(func(ctx context.Context, msg *Message) error {
+		delivered <- msg.Subject
+		return nil
+	}), QueueOptions{MaxAttempts: 1, PollInterval: time.Hour, BatchSize: 2, SendTimeout: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		q.Run(ctx)
+	}()
+	// Enqueueing wakes the queue long before the next poll.
+	for _, subject := range []string{"one", "two", "three"} {
+		if err := q.Enqueue(ctx, &Message{Subject: subject}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 3 {
+		select {
+		case <-delivered:
+		case <-time.After(5 * time.Second):
+			t.Fatal("mail was not delivered")
+		}
+	}
+	cancel()
+	<-done
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if !store.requeued {
+		t.Error("Run did not requeue interrupted mail first")
+	}
+	if len(store.sent) != 3 {
+		t.Errorf("sent %v", store.sent)
+	}
+}
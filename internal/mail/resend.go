@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+	"errors"
+
+	"github.com/resend/resend-go/v3"
+)
+
+type ResendMailer struct {
+	client *resend.Client
+}
+
+func NewResendMailer(apiKey string) (*ResendMailer, error) {
+	if apiKey == "" {
+		return nil, errors.New("mail: resend API key is required")
+	}
+	return &ResendMailer{client: resend.NewClient(apiKey)}, nil
+}
+
+func (m *ResendMailer) Send(ctx context.Context, msg *Message) error {
+	params := &resend.SendEmailRequest{
+		From:    msg.From,
+		To:      msg.To,
+		Subject: msg.Subject,
+		Html:    msg.HTML,
+		Text:    msg.Text,
+		ReplyTo: msg.ReplyTo,
+	}
+	_, err := m.client.Emails.SendWithContext(ctx, params)
+	return err
+}
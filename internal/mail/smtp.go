@@ -0,0 +1,99 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	StartTLS bool
+
+	// rootCAs verifies the server's certificate after STARTTLS instead of
+	// the system roots, for tests.
+	rootCAs *x509.CertPool
+}
+
+func NewSMTPMailer(host string, port int, username, password string, startTLS bool) (*SMTPMailer, error) {
+	if host == "" {
+		return nil, errors.New("mail: SMTP host is required")
+	}
+	if port == 0 {
+		port = 587
+	}
+	return &SMTPMailer{Host: host, Port: port, Username: username, Password: password, StartTLS: startTLS}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("smtp: dial %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.Host, RootCAs: m.rootCAs}); err != nil {
+			return fmt.Errorf("smtp: starttls: %w", err)
+		}
+	} else if m.StartTLS {
+		return errors.New("smtp: server does not support STARTTLS")
+	}
+
+	if m.Username != "" {
+		// PlainAuth refuses to send credentials over an unencrypted
+		// connection to anything but localhost.
+		auth := smtp.PlainAuth("", m.Username, m.Password, m.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+
+	from, err := envelopeAddress(msg.From)
+	if err != nil {
+		return err
+	}
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("smtp: MAIL FROM: %w", err)
+	}
+	for _, to := range msg.To {
+		rcpt, err := envelopeAddress(to)
+		if err != nil {
+			return err
+		}
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp: RCPT TO %s: %w", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: DATA: %w", err)
+	}
+	if _, err := w.Write(msg.Bytes()); err != nil {
+		return fmt.Errorf("smtp: write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return c.Quit()
+}
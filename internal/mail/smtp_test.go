@@ -0,0 +1,194 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTP is an SMTP server that accepts one connection and records what
+// the client sent.
+type fakeSMTP struct {
+	// startTLS offers STARTTLS with tlsConfig; authOK accepts any login.
+	startTLS  bool
+	authOK    bool
+	tlsConfig *tls.Config
+
+	mu   sync.Mutex
+	tls  bool
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		s.mu.Lock()
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			tp.PrintfLine("250-fake")
+			if s.startTLS && !s.tls {
+				tp.PrintfLine("250-STARTTLS")
+			}
+			tp.PrintfLine("250 AUTH PLAIN")
+		case "STARTTLS":
+			tp.PrintfLine("220 go ahead")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				s.mu.Unlock()
+				return
+			}
+			conn, tp, s.tls = tlsConn, textproto.NewConn(tlsConn), true
+		case "AUTH":
+			creds, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			s.auth = string(creds)
+			if s.authOK {
+				tp.PrintfLine("235 welcome")
+			} else {
+				tp.PrintfLine("535 go away")
+			}
+		case "MAIL":
+			s.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			s.rcpt = append(s.rcpt, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go on")
+			data, _ := tp.ReadDotBytes()
+			s.data = string(data)
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			s.mu.Unlock()
+			return
+		default:
+			tp.PrintfLine("502 unknown")
+		}
+		s.mu.Unlock()
+	}
+}
+
+// startSMTP serves s on a random local port and returns the mailer's host
+// and port, along with the roots that trust its certificate.
+func startSMTP(t *testing.T, s *fakeSMTP) (string, int, *x509.CertPool) {
+	t.Helper()
+	// The httptest certificate is valid for 127.0.0.1.
+	https := httptest.NewTLSServer(nil)
+	t.Cleanup(https.Close)
+	s.tlsConfig = &tls.Config{Certificates: https.TLS.Certificates}
+	roots := x509.NewCertPool()
+	roots.AddCert(https.Certificate())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		s.serve(conn)
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	n, _ := strconv.Atoi(port)
+	return host, n, roots
+}
+
+func TestSMTPMailer(t *testing.T) {
+	tests := []struct {
+		name string
+		// offerTLS and authOK configure the server.
+		offerTLS bool
+		authOK   bool
+		user     string
+		startTLS bool
+		wantErr  string
+		wantTLS  bool
+		wantAuth string
+	}{
+		{name: "starttls and auth", offerTLS: true, authOK: true, user: "lair", startTLS: true, wantTLS: true, wantAuth: "\x00lair\x00secret"},
+		{name: "opportunistic starttls", offerTLS: true, wantTLS: true},
+		{name: "plain"},
+		{name: "starttls required", user: "lair", startTLS: true, wantErr: "does not support STARTTLS"},
+		{name: "auth rejected", offerTLS: true, user: "lair", wantErr: "smtp: auth", wantTLS: true, wantAuth: "\x00lair\x00secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeSMTP{startTLS: tt.offerTLS, authOK: tt.authOK}
+			host, port, roots := startSMTP(t, s)
+			m, err := NewSMTPMailer(host, port, tt.user, "secret", tt.startTLS)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.rootCAs = roots
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err = m.Send(ctx, &Message{
+				From:    "Lair <lair@example.com>",
+				To:      []string{"a@example.com", "B <b@example.com>"},
+				Subject: "Hello",
+				Text:    "Hi.\n.\nBye.",
+			})
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.tls != tt.wantTLS || s.auth != tt.wantAuth {
+				t.Errorf("tls = %v, auth = %q, want %v, %q", s.tls, s.auth, tt.wantTLS, tt.wantAuth)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Send() error = %v, want %q", err, tt.wantErr)
+				}
+				if s.from != "" {
+					t.Errorf("mail was sent after the error, from %q", s.from)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if s.from != "lair@example.com" || strings.Join(s.rcpt, " ") != "a@example.com b@example.com" {
+				t.Errorf("envelope from %q to %q", s.from, s.rcpt)
+			}
+			// ReadDotBytes undoes the dot-stuffing and turns CRLF into LF.
+			if !strings.Contains(s.data, "Subject: Hello\n") || !strings.HasSuffix(s.data, "Hi.\n.\nBye.\n") {
+				t.Errorf("data = %q", s.data)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerRefusesBadAddresses(t *testing.T) {
+	s := &fakeSMTP{}
+	host, port, _ := startSMTP(t, s)
+	m, err := NewSMTPMailer(host, port, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.Send(context.Background(), &Message{From: "lair@example.com", To: []string{"not an address"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid address") {
+		t.Fatalf("Send() error = %v, want an invalid address", err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package metrics exports usage of the SSH app to Prometheus. Every method
+// is safe to call on a nil *Metrics, which records nothing, so callers don't
+// need to care whether metrics are enabled.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/promwish"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dragontui"
+
+// HealthFunc reports the database's health, as Database.CheckHealth does.
+type HealthFunc func(ctx context.Context) (map[string]string, error)
+
+type Metrics struct {
+	registry    *prometheus.Registry
+	pageViews   *prometheus.CounterVec
+	renders     *prometheus.HistogramVec
+	submissions *prometheus.CounterVec
+	deliveries  *prometheus.CounterVec
+}
+
+// New registers the app's metrics. active counts the open sessions and
+// health is polled on every scrape.
+func New(active func() int, health HealthFunc) *Metrics {
+	reg := prometheus.NewRegistry()
+	m := &Metrics{
+		registry: reg,
+		pageViews: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "page_views_total",
+			Help:      "Pages visitors navigated to, by route.",
+		}, []string{"route"}),
+		renders: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "render_duration_seconds",
+			Help:      "Time taken to render a page's view, by route.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+		}, []string{"route"}),
+		submissions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "contact_submissions_total",
+			Help:      "Contact form submissions, by outcome.",
+		}, []string{"outcome"}),
+		deliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "mail_deliveries_total",
+			Help:      "Attempts to deliver queued mail, by outcome.",
+		}, []string{"outcome"}),
+	}
+	reg.MustRegister(
+		m.pageViews, m.renders, m.submissions, m.deliveries,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sessions_active",
+			Help:      "SSH sessions open right now.",
+		}, func() float64 { return float64(active()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "database_up",
+			Help:      "Whether the database answered its health check.",
+		}, func() float64 {
+			if _, err := health(context.Background()); err != nil {
+				return 0
+			}
+			return 1
+		}),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Middleware counts sessions opened and finished, and how long they last,
+// using promwish's metrics.
+func (m *Metrics) Middleware() wish.Middleware {
+	if m == nil {
+		return func(next ssh.Handler) ssh.Handler { return next }
+	}
+	return promwish.MiddlewareRegistry(m.registry, prometheus.Labels{"app": namespace}, promwish.DefaultCommandFn)
+}
+
+// PageView counts a visit to route.
+func (m *Metrics) PageView(route string) {
+	if m == nil {
+		return
+	}
+	m.pageViews.WithLabelValues(route).Inc()
+}
+
+// ObserveRender records how long route took to render.
+func (m *Metrics) ObserveRender(route string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.renders.WithLabelValues(route).Observe(d.Seconds())
+}
+
+// Submission counts a contact submission with the given outcome, such as
+// queued or rejected.
+func (m *Metrics) Submission(outcome string) {
+	if m == nil {
+		return
+	}
+	m.submissions.WithLabelValues(outcome).Inc()
+}
+
+// Delivery counts a delivery attempt by the mail queue.
+func (m *Metrics) Delivery(outcome string) {
+	if m == nil {
+		return
+	}
+	m.deliveries.WithLabelValues(outcome).Inc()
+}
+
+// Serve exposes the metrics on addr at /metrics until ctx is done.
+func (m *Metrics) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("Serving metrics", "url", "http://"+addr+"/metrics")
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -2,85 +2,120 @@ package pages
 
 import (
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/theme"
 	"DragonTUI/internal/utils"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const useHighPerformanceRenderer = false
 
-var (
-	aboutTitlestyle = func() lipgloss.Style {
-		b := lipgloss.RoundedBorder()
-		b.Right = "├"
-		return lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
-	}()
-
-	infoStyle = func() lipgloss.Style {
-		b := lipgloss.RoundedBorder()
-		b.Left = "┤"
-		return aboutTitlestyle.BorderStyle(b)
-	}()
-)
+// titleStyle frames the title in page headers.
+func titleStyle(th *theme.Theme) lipgloss.Style {
+	b := lipgloss.RoundedBorder()
+	b.Right = "├"
+	return th.Style().BorderStyle(th.Outline(b)).Padding(0, 1)
+}
+
+// infoStyle frames the scroll position in page footers.
+func infoStyle(th *theme.Theme) lipgloss.Style {
+	b := lipgloss.RoundedBorder()
+	b.Left = "┤"
+	return titleStyle(th).BorderStyle(th.Outline(b))
+}
 
 type AboutModel struct {
 	Content  string
+	Markdown string
+	Version  uint64
 	Ready    bool
 	Viewport viewport.Model
 	Width    int
 	Height   int
 	Help     help.Model
 	KeyMap   AbtKeyMap
+	Source   *content.Store
+
+	find finder
+	themed
+}
+
+// SetTheme re-renders the resume in th's glamour style.
+func (m *AboutModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.Content = ""
+	m.syncContent()
+	return nil
 }
 
 func (m *AboutModel) Init() tea.Cmd {
 	return tea.SetWindowTitle("About me")
 }
 
+// CapturesKey keeps every key while the visitor types a search, and esc
+// for clearing the search once there is one.
+func (m *AboutModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.find.typing || m.find.searching() && isBackKey(msg)
+}
+
 func (m *AboutModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 	var (
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
 	switch msg := msg.(type) {
+	case ContentUpdatedMsg:
+		if msg.Source == content.Notifier(m.Source) {
+			m.syncContent()
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.find.typing {
+			submitted, cmd := m.find.update(msg)
+			if submitted {
+				m.refreshContent()
+				m.gotoHit(m.find.line())
+			}
+			return m, cmd
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 		case "ctrl+z":
 			return m, tea.Suspend
-		case "esc":
-			var cmd tea.Cmd
-			return GetMenuModel(m.Width, m.Height), tea.Batch(cmd, tea.SetWindowTitle("Dragon's Lair"))
+		case "esc", "backspace":
+			m.find.clear()
+			m.refreshContent()
+			return m, nil
+		case "/":
+			return m, m.find.start()
+		case "n":
+			m.gotoHit(m.find.next())
+			return m, nil
+		case "N":
+			m.gotoHit(m.find.prev())
+			return m, nil
 		case " ":
 			return m, cmd
 		}
 
 	case tea.WindowSizeMsg:
-		m.updateDimensions(msg.Width, m.Height)
-		headerHeight := lipgloss.Height(m.headerView())
-		footerHeight := lipgloss.Height(m.footerView())
-		verticalMarginHeight := headerHeight + footerHeight
-		if m.Width == 0 {
-			m.Viewport = viewport.New(m.Width, m.Height-verticalMarginHeight)
-			m.Viewport.YPosition = headerHeight
-			m.Viewport.SetContent(m.Content)
-			m.Ready = true
-
-			m.Viewport.YPosition = headerHeight + 1
-		} else {
-			m.Viewport.Width = m.Width
-			m.Viewport.Height = m.Height - verticalMarginHeight
+		rewrap := msg.Width != m.Width
+		m.updateDimensions(msg.Width, msg.Height)
+		m.ensureViewport()
+		if rewrap {
+			m.Content = ""
+			m.syncContent()
 		}
-
 	}
 
 	m.Viewport, cmd = m.Viewport.Update(msg)
@@ -89,78 +124,138 @@ func (m *AboutModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func (m *AboutModel) View() string {
-	if len(m.Content) == 0 {
-		fileContent, err := os.ReadFile("resume.md")
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Could not load file:", err)
-			os.Exit(1)
-		}
-
-		renderedContent, err := glamour.Render(string(fileContent), "dracula")
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Could not render content:", err)
-			os.Exit(1)
-		}
-
-		m.Content = renderedContent
+// Enter opens the page with a search already applied when navigated to from
+// the search page with a "q" parameter, jumping to occurrence "match".
+func (m *AboutModel) Enter(params map[string]string) tea.Cmd {
+	q := params["q"]
+	if q == "" {
+		return nil
 	}
+	m.syncContent()
+	m.ensureViewport()
+	m.find.clear()
+	m.find.query = q
+	m.refreshContent()
+	n, _ := strconv.Atoi(params["match"])
+	m.gotoHit(m.find.seek(n))
+	return nil
+}
 
+func (m *AboutModel) ensureViewport() {
 	headerHeight := lipgloss.Height(m.headerView())
 	footerHeight := lipgloss.Height(m.footerView())
-	verticalMarginHeight := headerHeight + footerHeight
+	verticalMarginHeight := headerHeight + footerHeight + lipgloss.Height(m.Help.View(m.KeyMap))
+	if status := m.find.view(m.th); status != "" {
+		verticalMarginHeight += lipgloss.Height(status)
+	}
+	viewportHeight := max(0, m.Height-verticalMarginHeight)
 	if !m.Ready {
-		m.Viewport = viewport.New(m.Width, m.Height-verticalMarginHeight)
+		m.Viewport = viewport.New(m.Width, viewportHeight)
 		m.Viewport.YPosition = headerHeight
-		m.Viewport.SetContent(m.Content)
+		m.Viewport.SetContent(m.displayed())
 		m.Ready = true
 
 		m.Viewport.YPosition = headerHeight + 1
 	} else {
 		m.Viewport.Width = m.Width
-		m.Viewport.Height = m.Height - verticalMarginHeight
+		m.Viewport.Height = viewportHeight
+	}
+}
+
+// displayed is the rendered resume with any search matches highlighted.
+func (m *AboutModel) displayed() string {
+	if !m.find.searching() {
+		return m.Content
+	}
+	return m.find.apply(m.Markdown, m.Content, m.th.Markdown(), m.th.Profile(), m.Width)
+}
+
+func (m *AboutModel) refreshContent() {
+	if !m.Ready {
+		return
+	}
+	offset := m.Viewport.YOffset
+	m.Viewport.SetContent(m.displayed())
+	m.Viewport.SetYOffset(offset)
+}
+
+func (m *AboutModel) gotoHit(line int, ok bool) {
+	if ok {
+		m.Viewport.SetYOffset(line)
 	}
-	m.Help.Styles.ShortDesc = utils.Style.Faint(true).UnsetBlink()
+}
+
+func (m *AboutModel) View() string {
+	m.syncContent()
+	m.ensureViewport()
+
+	m.Help.Styles.ShortDesc = m.th.Faint()
 	m.Help.ShortSeparator = " • "
-	m.Help.Styles.ShortSeparator = lipgloss.NewStyle().Blink(true).Foreground(lipgloss.Color("#334dcc"))
-	m.Help.Styles.ShortKey = lipgloss.NewStyle().
+	m.Help.Styles.ShortSeparator = m.th.Style().Foreground(m.th.Muted)
+	m.Help.Styles.ShortKey = m.th.Style().
 		Italic(true).
-		Foreground(lipgloss.Color("#FFF7DB"))
+		Foreground(m.th.Text)
 	s := fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.Viewport.View(), m.footerView())
-	s += fmt.Sprintf("\n%s", m.Help.View(m.KeyMap))
+	if status := m.find.view(m.th); status != "" {
+		s += "\n" + status
+	}
+	s += "\n" + helpView(m.Help, m.KeyMap)
 
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
 }
 
+// syncContent swaps in the latest resume snapshot, keeping the reader's
+// scroll position where the new content is long enough.
+func (m *AboutModel) syncContent() {
+	snap := m.Source.Current()
+	if snap.Version == m.Version && m.Content != "" {
+		return
+	}
+	m.Version = snap.Version
+	m.Markdown = snap.Markdown
+	rendered, err := snap.RenderedIn(m.th.Markdown(), m.th.Profile(), m.Width)
+	switch {
+	case err != nil:
+		m.Content = fmt.Sprintf("Resume unavailable\n\n%v\n", err)
+	case rendered == "":
+		m.Content = "Loading resume..."
+	default:
+		m.Content = rendered
+	}
+	m.refreshContent()
+}
+
 func (m *AboutModel) updateDimensions(width, height int) {
 	m.Width = width
 	m.Height = height
+	m.Help.Width = width
 }
 
 func (m *AboutModel) headerView() string {
 	var title strings.Builder
-	for i, v := range utils.Colors {
+	colors := m.th.Grid(1, 5)
+	for i, v := range colors {
 		const offset = 2
 		c := lipgloss.Color(v[0])
-		fmt.Fprint(&title, aboutTitlestyle.MarginLeft(i*offset).Background(c))
-		if i < len(utils.Colors)-1 {
+		fmt.Fprint(&title, titleStyle(m.th).MarginLeft(i*offset).Background(c))
+		if i < len(colors)-1 {
 			title.WriteRune('\n')
 		}
 	}
-	s := aboutTitlestyle.Render(utils.Rainbow(lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("#ffffff")), "Elton Mpinyuri", utils.Blends))
-	line := strings.Repeat("─", utils.Max(0, m.Viewport.Width-lipgloss.Width(s)))
-	scr := lipgloss.JoinHorizontal(lipgloss.Center, s, lipgloss.NewStyle().Foreground(lipgloss.Color("#e60000")).Render(line))
+	s := titleStyle(m.th).Render(m.th.Rainbow(m.th.Style().Bold(true).Background(m.th.OnAccent), "Elton Mpinyuri"))
+	line := m.th.Rule(utils.Max(0, m.Viewport.Width-lipgloss.Width(s)))
+	scr := lipgloss.JoinHorizontal(lipgloss.Center, s, m.th.Style().Foreground(m.th.Error).Render(line))
 	return scr
 }
 
 func (m *AboutModel) footerView() string {
-	info := infoStyle.Render(fmt.Sprintf("%3.f%%", m.Viewport.ScrollPercent()*100))
-	line := strings.Repeat("─", utils.Max(0, m.Viewport.Width-lipgloss.Width(info)))
-	return lipgloss.JoinHorizontal(lipgloss.Center, lipgloss.NewStyle().Foreground(lipgloss.Color("#009900")).Render(line), lipgloss.NewStyle().Foreground(lipgloss.Color("#e6f733")).Bold(true).Render(info))
+	info := infoStyle(m.th).Render(fmt.Sprintf("%3.f%%", m.Viewport.ScrollPercent()*100))
+	line := m.th.Rule(utils.Max(0, m.Viewport.Width-lipgloss.Width(info)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, m.th.Style().Foreground(m.th.Success).Render(line), m.th.Style().Foreground(m.th.Highlight).Bold(true).Render(info))
 }
 
-func NewAboutModel(width int, height int) *AboutModel {
-	return &AboutModel{Width: width, Height: height, Help: help.New(), KeyMap: AbtKeyMap{}}
+func NewAboutModel(width int, height int, source *content.Store) *AboutModel {
+	return &AboutModel{Width: width, Height: height, Help: help.New(), KeyMap: AbtKeyMap{}, Source: source, find: newFinder()}
 }
 
 type AbtKeyMap struct{}
@@ -169,7 +264,10 @@ func (k AbtKeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("↑/k", "move up")),
 		key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("↓/j", "move down")),
-		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Back to Menu")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		key.NewBinding(key.WithKeys("n", "N"), key.WithHelp("n/N", "next/prev match")),
+		key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc", "back")),
+		key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "forward")),
 	}
 }
 
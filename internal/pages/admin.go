@@ -0,0 +1,133 @@
+package pages
+
+import (
+	"fmt"
+
+	"DragonTUI/internal/server"
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SessionRegistry lists the sessions connected to the server and can
+// disconnect them.
+type SessionRegistry interface {
+	Sessions() []server.SessionInfo
+	Disconnect(id uint64) error
+}
+
+// Broadcaster delivers a message to every connected session.
+type Broadcaster interface {
+	Broadcast(msg tea.Msg)
+}
+
+// AdminModel is the entry point of the admin pages. Its routes are only
+// registered for sessions whose key is an authorized admin.
+type AdminModel struct {
+	Width  int
+	Height int
+	Help   help.Model
+	KeyMap AdminKeyMap
+	List   list.Model
+	themed
+}
+
+type adminItem struct {
+	title, desc string
+	route       Route
+}
+
+func (i adminItem) Title() string       { return i.title }
+func (i adminItem) Description() string { return i.desc }
+func (i adminItem) FilterValue() string { return i.title }
+
+func NewAdminModel(width, height int) *AdminModel {
+	items := []list.Item{
+		adminItem{title: "Sessions", desc: "Who is connected right now", route: RouteAdminSessions},
+		adminItem{title: "Messages", desc: "Read messages left by visitors", route: RouteInbox},
+		adminItem{title: "Menu", desc: "Rename, reorder and hide menu entries", route: RouteAdminMenu},
+		adminItem{title: "Broadcast", desc: "Send a notice to every visitor", route: RouteAdminBroadcast},
+	}
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	l.Title = "Admin"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+
+	m := &AdminModel{
+		Width:  width,
+		Height: height,
+		Help:   help.New(),
+		KeyMap: AdminKeyMap{},
+		List:   l,
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *AdminModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.List.SetDelegate(newListDelegate(th))
+	return nil
+}
+
+func (m *AdminModel) Init() tea.Cmd {
+	return tea.SetWindowTitle("Admin")
+}
+
+func (m *AdminModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "enter":
+			if i, ok := m.List.SelectedItem().(adminItem); ok {
+				return m, Navigate(i.route, nil)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return m, cmd
+}
+
+func (m *AdminModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.List.SetSize(listSize(width, height, 2))
+}
+
+func (m *AdminModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+	s := fmt.Sprintf("%s\n%s", m.List.View(), m.Help.View(m.KeyMap))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type AdminKeyMap struct{}
+
+func (k AdminKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}
+}
+
+func (k AdminKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,138 @@
+package pages
+
+import (
+	"fmt"
+
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NoticeMsg is a notice an admin broadcast to every session. It is shown
+// above whatever page the visitor is on.
+type NoticeMsg struct {
+	Text string
+}
+
+// RenderNotice renders text as a banner width cells wide, in th's accent
+// colors.
+func RenderNotice(th *theme.Theme, text string, width int) string {
+	return th.Style().
+		Bold(true).
+		Foreground(th.OnAccent).
+		Background(th.Accent).
+		Padding(0, 1).
+		Width(width).
+		Render(text)
+}
+
+// AdminBroadcastModel sends a notice to every connected session.
+type AdminBroadcastModel struct {
+	Width       int
+	Height      int
+	Help        help.Model
+	KeyMap      AdminBroadcastKeyMap
+	Input       textinput.Model
+	TitleStyle  lipgloss.Style
+	Broadcaster Broadcaster
+	Audit       Auditor
+	// Sent is the last notice sent from this page.
+	Sent string
+	themed
+}
+
+func NewAdminBroadcastModel(width, height int, broadcaster Broadcaster, audit Auditor) *AdminBroadcastModel {
+	ti := textinput.New()
+	ti.Placeholder = "The lair closes for maintenance at noon"
+	ti.Prompt = "Notice: "
+	ti.CharLimit = 200
+
+	m := &AdminBroadcastModel{
+		Width:       width,
+		Height:      height,
+		Help:        help.New(),
+		KeyMap:      AdminBroadcastKeyMap{},
+		Input:       ti,
+		TitleStyle:  list.DefaultStyles().Title.Margin(1),
+		Broadcaster: broadcaster,
+		Audit:       audit,
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *AdminBroadcastModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Broadcast"), m.Input.Focus())
+}
+
+// CapturesKey keeps backspace for the notice being typed.
+func (m *AdminBroadcastModel) CapturesKey(msg tea.KeyMsg) bool {
+	return msg.String() == "backspace"
+}
+
+func (m *AdminBroadcastModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "enter":
+			text := m.Input.Value()
+			if text == "" {
+				return m, nil
+			}
+			m.Broadcaster.Broadcast(NoticeMsg{Text: text})
+			m.Sent = text
+			m.Input.Reset()
+			return m, recordAudit(m.Audit, "admin.broadcast", text)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+func (m *AdminBroadcastModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.Input.Width = min(60, width-lipgloss.Width(m.Input.Prompt)-1)
+}
+
+func (m *AdminBroadcastModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+
+	title := m.TitleStyle.Render("Broadcast")
+	body := fmt.Sprintf("%s\n%s\n", title, m.Input.View())
+	if m.Sent != "" {
+		body += "\n" + m.th.Style().Faint(true).Render("Sent: "+m.Sent) + "\n"
+	}
+	s := fmt.Sprintf("%s\n%s", body, m.Help.View(m.KeyMap))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type AdminBroadcastKeyMap struct{}
+
+func (k AdminBroadcastKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "send to everyone")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}
+}
+
+func (k AdminBroadcastKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
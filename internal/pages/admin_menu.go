@@ -0,0 +1,329 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"DragonTUI/internal/db"
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// AdminMenuStore is where the menu editor keeps its changes.
+type AdminMenuStore interface {
+	MenuStore
+	SaveMenuItems(ctx context.Context, items []db.MenuItem) error
+}
+
+// AdminMenuModel renames, reorders and hides entries of the public menu.
+// Every change is saved at once and open menus are told to reload.
+type AdminMenuModel struct {
+	Width       int
+	Height      int
+	Help        help.Model
+	KeyMap      AdminMenuKeyMap
+	List        list.Model
+	Store       AdminMenuStore
+	Broadcaster Broadcaster
+	Audit       Auditor
+	Projects    bool
+	Entries     []MenuEntry
+	Err         error
+
+	// editing is true while the title and description inputs are shown.
+	editing bool
+	inputs  [2]textinput.Model
+	focus   int
+	themed
+}
+
+type menuEntryItem struct {
+	entry MenuEntry
+}
+
+func (i menuEntryItem) Title() string {
+	if i.entry.Hidden {
+		return i.entry.Title + " (hidden)"
+	}
+	return i.entry.Title
+}
+
+func (i menuEntryItem) Description() string { return i.entry.Description }
+func (i menuEntryItem) FilterValue() string { return i.entry.Title }
+
+type adminMenuLoadedMsg struct {
+	overrides []db.MenuItem
+	err       error
+}
+
+type adminMenuSavedMsg struct {
+	err error
+}
+
+func NewAdminMenuModel(width, height int, store AdminMenuStore, broadcaster Broadcaster, audit Auditor, projects bool) *AdminMenuModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	l.Title = "Menu"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+
+	var inputs [2]textinput.Model
+	for i, placeholder := range []string{"Title", "Description"} {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = placeholder
+		inputs[i].Prompt = placeholder + ": "
+		inputs[i].CharLimit = 80
+	}
+
+	m := &AdminMenuModel{
+		Width:       width,
+		Height:      height,
+		Help:        help.New(),
+		KeyMap:      AdminMenuKeyMap{},
+		List:        l,
+		Store:       store,
+		Broadcaster: broadcaster,
+		Audit:       audit,
+		Projects:    projects,
+		inputs:      inputs,
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *AdminMenuModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.List.SetDelegate(newListDelegate(th))
+	return nil
+}
+
+func (m *AdminMenuModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Menu"), m.load())
+}
+
+func (m *AdminMenuModel) load() tea.Cmd {
+	store := m.Store
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		overrides, err := store.ListMenuItems(ctx)
+		return adminMenuLoadedMsg{overrides: overrides, err: err}
+	}
+}
+
+// save stores the whole menu as it is now, in its current order.
+func (m *AdminMenuModel) save() tea.Cmd {
+	items := make([]db.MenuItem, len(m.Entries))
+	for i, e := range m.Entries {
+		items[i] = db.MenuItem{
+			Key:         e.Item.Key(),
+			Title:       e.Title,
+			Description: e.Description,
+			Position:    i,
+			Hidden:      e.Hidden,
+		}
+	}
+	store, broadcaster, audit := m.Store, m.Broadcaster, m.Audit
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err := store.SaveMenuItems(ctx, items)
+		if err == nil && broadcaster != nil {
+			broadcaster.Broadcast(MenuChangedMsg{})
+		}
+		if err == nil && audit != nil {
+			audit.Record("admin.menu", menuSummary(items))
+		}
+		return adminMenuSavedMsg{err: err}
+	}
+}
+
+// menuSummary lists the saved entries in order for the audit log, such as
+// "about, contact, projects (hidden)".
+func menuSummary(items []db.MenuItem) string {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+		if item.Hidden {
+			keys[i] += " (hidden)"
+		}
+	}
+	return strings.Join(keys, ", ")
+}
+
+func (m *AdminMenuModel) setEntries(entries []MenuEntry) tea.Cmd {
+	m.Entries = entries
+	items := make([]list.Item, len(entries))
+	for i, e := range entries {
+		items[i] = menuEntryItem{entry: e}
+	}
+	return m.List.SetItems(items)
+}
+
+// move swaps the selected entry with its neighbour by delta places.
+func (m *AdminMenuModel) move(delta int) tea.Cmd {
+	i := m.List.Index()
+	j := i + delta
+	if i < 0 || j < 0 || j >= len(m.Entries) {
+		return nil
+	}
+	entries := append([]MenuEntry(nil), m.Entries...)
+	entries[i], entries[j] = entries[j], entries[i]
+	cmd := m.setEntries(entries)
+	m.List.Select(j)
+	return tea.Batch(cmd, m.save())
+}
+
+func (m *AdminMenuModel) startEditing() tea.Cmd {
+	i := m.List.Index()
+	if i < 0 || i >= len(m.Entries) {
+		return nil
+	}
+	m.editing = true
+	m.focus = 0
+	m.inputs[0].SetValue(m.Entries[i].Title)
+	m.inputs[1].SetValue(m.Entries[i].Description)
+	m.inputs[1].Blur()
+	return m.inputs[0].Focus()
+}
+
+func (m *AdminMenuModel) updateEditing(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		return nil
+	case "tab", "shift+tab", "up", "down":
+		m.inputs[m.focus].Blur()
+		m.focus = 1 - m.focus
+		return m.inputs[m.focus].Focus()
+	case "enter":
+		m.editing = false
+		i := m.List.Index()
+		entries := append([]MenuEntry(nil), m.Entries...)
+		if title := m.inputs[0].Value(); title != "" {
+			entries[i].Title = title
+		}
+		entries[i].Description = m.inputs[1].Value()
+		return tea.Batch(m.setEntries(entries), m.save())
+	}
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return cmd
+}
+
+// CapturesKey keeps every key while an entry is being edited.
+func (m *AdminMenuModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.editing
+}
+
+func (m *AdminMenuModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+
+	case adminMenuLoadedMsg:
+		m.Err = msg.err
+		return m, m.setEntries(ApplyMenuOverrides(DefaultMenu(m.Projects), msg.overrides))
+
+	case adminMenuSavedMsg:
+		m.Err = msg.err
+		if msg.err != nil {
+			return m, m.load()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.editing {
+			return m, m.updateEditing(msg)
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "e", "enter":
+			return m, m.startEditing()
+		case "h":
+			i := m.List.Index()
+			if i < 0 || i >= len(m.Entries) {
+				return m, nil
+			}
+			entries := append([]MenuEntry(nil), m.Entries...)
+			entries[i].Hidden = !entries[i].Hidden
+			return m, tea.Batch(m.setEntries(entries), m.save())
+		case "K", "shift+up":
+			return m, m.move(-1)
+		case "J", "shift+down":
+			return m, m.move(1)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return m, cmd
+}
+
+func (m *AdminMenuModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	// Leave room for the edit fields below the list.
+	m.List.SetSize(listSize(width, height, 5))
+	for i := range m.inputs {
+		m.inputs[i].Width = min(60, width-lipgloss.Width(m.inputs[i].Prompt)-1)
+	}
+}
+
+func (m *AdminMenuModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+
+	body := m.List.View()
+	if m.editing {
+		body += fmt.Sprintf("\n%s\n%s", m.inputs[0].View(), m.inputs[1].View())
+	}
+	if m.Err != nil {
+		body += "\n" + m.th.Style().Foreground(m.th.Error).Render(m.Err.Error())
+	}
+	s := fmt.Sprintf("%s\n%s", body, m.Help.View(m.KeyMap.withEditing(m.editing)))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type AdminMenuKeyMap struct {
+	editing bool
+}
+
+func (k AdminMenuKeyMap) withEditing(editing bool) AdminMenuKeyMap {
+	k.editing = editing
+	return k
+}
+
+func (k AdminMenuKeyMap) ShortHelp() []key.Binding {
+	if k.editing {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next field")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "save")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		}
+	}
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+		key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "hide/show")),
+		key.NewBinding(key.WithKeys("K", "J"), key.WithHelp("K/J", "move up/down")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}
+}
+
+func (k AdminMenuKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,191 @@
+package pages
+
+import (
+	"fmt"
+	"time"
+
+	"DragonTUI/internal/server"
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const sessionsRefreshInterval = 2 * time.Second
+
+// AdminSessionsModel lists connected sessions, refreshing while it is open,
+// and disconnects them.
+type AdminSessionsModel struct {
+	Width    int
+	Height   int
+	Help     help.Model
+	KeyMap   AdminSessionsKeyMap
+	List     list.Model
+	Sessions SessionRegistry
+	Audit    Auditor
+	Err      error
+	// tick tells this page's refresh ticks apart from those of an earlier
+	// visit, so leaving and returning doesn't double the refresh rate.
+	tick int
+	themed
+}
+
+type sessionItem struct {
+	info server.SessionInfo
+}
+
+func (i sessionItem) Title() string {
+	page := i.info.Page
+	if page == "" {
+		page = "-"
+	}
+	return fmt.Sprintf("#%d %s@%s on %s", i.info.ID, i.info.User, i.info.RemoteAddr, page)
+}
+
+func (i sessionItem) Description() string {
+	key := i.info.Fingerprint
+	if key == "" {
+		key = "no key"
+	}
+	return fmt.Sprintf("%s ago · %s %dx%d · %s · %s", time.Since(i.info.ConnectedAt).Round(time.Second),
+		i.info.Term, i.info.Width, i.info.Height, key, i.info.CorrelationID)
+}
+
+func (i sessionItem) FilterValue() string {
+	return i.info.User + " " + i.info.RemoteAddr + " " + i.info.Fingerprint + " " + i.info.CorrelationID
+}
+
+type sessionsTickMsg struct {
+	tick int
+}
+
+func NewAdminSessionsModel(width, height int, sessions SessionRegistry, audit Auditor) *AdminSessionsModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+
+	m := &AdminSessionsModel{
+		Width:    width,
+		Height:   height,
+		Help:     help.New(),
+		KeyMap:   AdminSessionsKeyMap{},
+		List:     l,
+		Sessions: sessions,
+		Audit:    audit,
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *AdminSessionsModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.List.SetDelegate(newListDelegate(th))
+	return nil
+}
+
+func (m *AdminSessionsModel) Init() tea.Cmd {
+	m.tick++
+	return tea.Batch(tea.SetWindowTitle("Sessions"), m.refresh())
+}
+
+func (m *AdminSessionsModel) refresh() tea.Cmd {
+	sessions := m.Sessions.Sessions()
+	m.List.Title = fmt.Sprintf("Sessions (%d)", len(sessions))
+	items := make([]list.Item, len(sessions))
+	for i, s := range sessions {
+		items[i] = sessionItem{info: s}
+	}
+	tick := m.tick
+	return tea.Batch(m.List.SetItems(items), tea.Tick(sessionsRefreshInterval, func(time.Time) tea.Msg {
+		return sessionsTickMsg{tick: tick}
+	}))
+}
+
+// CapturesKey keeps every key while the visitor types a filter, and esc
+// for clearing it once applied.
+func (m *AdminSessionsModel) CapturesKey(msg tea.KeyMsg) bool {
+	switch m.List.FilterState() {
+	case list.Filtering:
+		return true
+	case list.FilterApplied:
+		return isBackKey(msg)
+	}
+	return false
+}
+
+func (m *AdminSessionsModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+
+	case sessionsTickMsg:
+		if msg.tick != m.tick {
+			return m, nil
+		}
+		return m, m.refresh()
+
+	case tea.KeyMsg:
+		if m.List.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "esc", "backspace":
+			m.List.ResetFilter()
+			return m, nil
+		case "x":
+			i, ok := m.List.SelectedItem().(sessionItem)
+			if !ok {
+				return m, nil
+			}
+			if m.Err = m.Sessions.Disconnect(i.info.ID); m.Err != nil {
+				return m, nil
+			}
+			return m, recordAudit(m.Audit, "admin.disconnect", fmt.Sprintf("%s %s@%s", i.info.CorrelationID, i.info.User, i.info.RemoteAddr))
+		}
+	}
+
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return m, cmd
+}
+
+func (m *AdminSessionsModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.List.SetSize(listSize(width, height, 2))
+}
+
+func (m *AdminSessionsModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+	body := m.List.View()
+	if m.Err != nil {
+		body += "\n" + m.th.Style().Foreground(m.th.Error).Render(m.Err.Error())
+	}
+	s := fmt.Sprintf("%s\n%s", body, m.Help.View(m.KeyMap))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type AdminSessionsKeyMap struct{}
+
+func (k AdminSessionsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "disconnect")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}
+}
+
+func (k AdminSessionsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
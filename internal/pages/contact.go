@@ -1,18 +1,22 @@
 package pages
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"html"
 	"regexp"
+	"time"
 
-	"DragonTUI/internal/utils"
+	"DragonTUI/internal/abuse"
+	"DragonTUI/internal/db"
+	"DragonTUI/internal/mail"
+	"DragonTUI/internal/theme"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/resend/resend-go/v3"
 )
 
 type ContactModel struct {
@@ -23,7 +27,62 @@ type ContactModel struct {
 	KeyMap      ContactKeyMap
 	FeedbackMsg FeedbackMsg
 	EmailSent   bool
+	EmailQueued bool
 	EmailError  error
+	Submitted   bool
+	Visitor     Visitor
+	ContactOptions
+	// RetryAfter is how long the visitor has to wait before they can send
+	// another message, set when the last one was over the limit.
+	RetryAfter time.Duration
+	// Rejected is why the last message was refused by the filter.
+	Rejected string
+	themed
+}
+
+// ContactOptions are the services the contact page sends messages through.
+type ContactOptions struct {
+	Store  MessageStore
+	Mailer mail.Mailer
+	Queue  MailQueue
+	Limit  *SubmissionLimit
+	// Filter screens messages for spam; nil lets everything through.
+	Filter abuse.Filter
+	// Challenge asks visitors without a key to solve a sum before sending.
+	Challenge bool
+	// Work, if set, is told about submissions in flight so that shutdown
+	// waits for them to be saved.
+	Work WorkTracker
+	// Observer, if set, is told the outcome of every submission.
+	Observer SubmissionObserver
+	// Audit, if set, records every submission in the audit log.
+	Audit Auditor
+}
+
+// SubmissionObserver counts contact submissions by outcome: sent, queued,
+// quarantined, failed, rejected or rate_limited.
+type SubmissionObserver interface {
+	Submission(outcome string)
+}
+
+// WorkTracker keeps count of work that must finish before the server stops.
+type WorkTracker interface {
+	// Hold registers a piece of work; call the returned func when it is
+	// done.
+	Hold() func()
+}
+
+// MailQueue hands mail to the background delivery worker.
+type MailQueue interface {
+	Enqueue(ctx context.Context, msg *mail.Message, messageID int64) error
+}
+
+// MessageStore persists contact submissions so they survive a failed
+// delivery, and remembers the name a keyed visitor signed with.
+type MessageStore interface {
+	CreateMessage(ctx context.Context, m *db.Message) error
+	SetMessageDeliveryStatus(ctx context.Context, id int64, status db.DeliveryStatus, deliveryErr string) error
+	SetUserName(ctx context.Context, id int64, name string) error
 }
 
 type FeedbackMsg struct {
@@ -33,104 +92,288 @@ type FeedbackMsg struct {
 }
 
 type EmailSentMsg struct {
-	success bool
-	err     error
+	success    bool
+	queued     bool
+	err        error
+	retryAfter time.Duration
+	rejected   string
+	// quarantined messages are reported to the sender as queued.
+	quarantined bool
+}
+
+// outcome names the result of a submission for a SubmissionObserver.
+func (msg EmailSentMsg) outcome() string {
+	switch {
+	case msg.retryAfter > 0:
+		return "rate_limited"
+	case msg.rejected != "":
+		return "rejected"
+	case msg.quarantined:
+		return "quarantined"
+	case msg.err != nil:
+		return "failed"
+	case msg.queued:
+		return "queued"
+	default:
+		return "sent"
+	}
 }
 
-func sendEmail(name, email, message string) tea.Cmd {
+func sendEmail(mailer mail.Mailer, name, email, message string) tea.Cmd {
 	return func() tea.Msg {
-		resendkey := os.Getenv("RESEND_API_KEY")
-		if resendkey == "" {
-			return EmailSentMsg{success: false, err: fmt.Errorf("RESEND_API_KEY not set")}
+		if mailer == nil {
+			return EmailSentMsg{success: false, err: fmt.Errorf("mail delivery is not configured")}
 		}
 
-		client := resend.NewClient(resendkey)
-		params := &resend.SendEmailRequest{
-			From:    "DragonTUI <noreply@resend.dev>",
-			To:      []string{"ebmpinyuri@gmail.com"},
-			Html:    fmt.Sprintf("<p><strong>Email:</strong> %s</p><p><strong>Message:</strong></p><p>%s</p>", email, message),
-			Subject: fmt.Sprintf("New Contact from %s", name),
-			ReplyTo: email,
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := mailer.Send(ctx, contactEmail(name, email, message))
+		if err != nil {
+			return EmailSentMsg{success: false, err: err}
 		}
+		return EmailSentMsg{success: true, err: nil}
+	}
+}
 
-		sent, err := client.Emails.Send(params)
+func contactEmail(name, email, message string) *mail.Message {
+	return &mail.Message{
+		Subject: fmt.Sprintf("New Contact from %s", name),
+		ReplyTo: email,
+		Text:    fmt.Sprintf("Email: %s\n\nMessage:\n%s\n", email, message),
+		HTML: fmt.Sprintf("<p><strong>Email:</strong> %s</p><p><strong>Message:</strong></p><p>%s</p>",
+			html.EscapeString(email), html.EscapeString(message)),
+	}
+}
+
+// submitMessage stores the message and queues it for delivery. Without a
+// store or queue it falls back to sending the email directly. Messages over
+// the submission limit or rejected by filter are dropped; if the limit can't
+// be checked the message goes through. Quarantined messages are stored
+// without being emailed, and the sender is told they were queued; if they
+// can't be stored, the submission fails.
+func submitMessage(opts ContactOptions, visitor Visitor, feedback FeedbackMsg) tea.Cmd {
+	// Hold the work before the command runs, so a shutdown that starts in
+	// between still waits for it.
+	release := func() {}
+	if opts.Work != nil {
+		release = opts.Work.Hold()
+	}
+	store, queue, mailer := opts.Store, opts.Queue, opts.Mailer
+	logger := visitor.Logger()
+	send := func() EmailSentMsg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		retryAfter, err := opts.Limit.Allow(ctx, visitor)
 		if err != nil {
-			return EmailSentMsg{success: false, err: err}
+			logger.Error("Could not check submission limit", "err", err)
+		}
+		if retryAfter > 0 {
+			return EmailSentMsg{retryAfter: retryAfter}
 		}
 
-		fmt.Printf("Email sent successfully! ID: %s\n", sent.Id)
-		return EmailSentMsg{success: true, err: nil}
+		var verdict abuse.Verdict
+		if opts.Filter != nil {
+			verdict, err = opts.Filter.Check(ctx, abuse.Submission{
+				Name:           feedback.name,
+				Email:          feedback.email,
+				Body:           feedback.message,
+				KeyFingerprint: visitor.KeyFingerprint,
+				IP:             visitor.IP(),
+			})
+			if err != nil {
+				logger.Error("Could not screen contact message", "err", err)
+			}
+		}
+		if verdict.Action == abuse.Reject {
+			logger.Warn("Rejected contact message", "email", feedback.email, "reason", verdict.Reason)
+			return EmailSentMsg{rejected: verdict.Reason}
+		}
+
+		msg := &db.Message{
+			Name:           feedback.name,
+			Email:          feedback.email,
+			Body:           feedback.message,
+			SSHUser:        visitor.User,
+			KeyFingerprint: visitor.KeyFingerprint,
+			RemoteAddr:     visitor.RemoteAddr,
+		}
+		if verdict.Action == abuse.Quarantine {
+			msg.DeliveryStatus = db.DeliveryQuarantined
+			msg.DeliveryError = verdict.Reason
+		}
+		saved := false
+		if store != nil {
+			if err := store.CreateMessage(ctx, msg); err != nil {
+				logger.Error("Could not save contact message", "err", err)
+			} else {
+				saved = true
+			}
+			if visitor.UserID != 0 && visitor.Name == "" {
+				if err := store.SetUserName(ctx, visitor.UserID, feedback.name); err != nil {
+					logger.Error("Could not remember name of user", "user", visitor.UserID, "err", err)
+				}
+			}
+		}
+
+		// A quarantined message is never emailed, even if it couldn't be
+		// held for review.
+		if verdict.Action == abuse.Quarantine {
+			if !saved {
+				logger.Error("Could not hold quarantined contact message", "reason", verdict.Reason)
+				return EmailSentMsg{err: fmt.Errorf("your message could not be saved")}
+			}
+			logger.Warn("Quarantined contact message", "message", msg.ID, "reason", verdict.Reason)
+			return EmailSentMsg{success: true, queued: true, quarantined: true}
+		}
+
+		if saved && queue != nil {
+			err := queue.Enqueue(ctx, contactEmail(feedback.name, feedback.email, feedback.message), msg.ID)
+			if err == nil {
+				return EmailSentMsg{success: true, queued: true}
+			}
+			logger.Error("Could not queue contact message", "message", msg.ID, "err", err)
+		}
+
+		result := sendEmail(mailer, feedback.name, feedback.email, feedback.message)().(EmailSentMsg)
+
+		if saved {
+			status, deliveryErr := db.DeliverySent, ""
+			if result.err != nil {
+				status, deliveryErr = db.DeliveryFailed, result.err.Error()
+			}
+			if err := store.SetMessageDeliveryStatus(ctx, msg.ID, status, deliveryErr); err != nil {
+				logger.Error("Could not update delivery status", "message", msg.ID, "err", err)
+			}
+		}
+		return result
+	}
+	return func() tea.Msg {
+		defer release()
+		result := send()
+		if opts.Observer != nil {
+			opts.Observer.Submission(result.outcome())
+		}
+		if opts.Audit != nil {
+			opts.Audit.Record("contact", fmt.Sprintf("%s from %s", result.outcome(), feedback.email))
+		}
+		return result
 	}
 }
 
-func newForm() *huh.Form {
-	return huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("CodeDragon Mailer").
-				Description("Full Name: ").
-				Placeholder("Enter your full name here").
-				Validate(func(str string) error {
-					if str == "" {
-						return fmt.Errorf("name required, try again")
-					}
-					return nil
-				}).
-				Key("name"),
-			huh.NewInput().
-				Description("Email: ").
-				Placeholder("Enter your email here").
-				Key("email").
-				Validate(func(str string) error {
-					re := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-					if str != "" {
-						if !re.MatchString(str) {
-							return fmt.Errorf("invalid Email, try again")
-						}
-					} else {
-						return fmt.Errorf("email required, try again")
-					}
-					return nil
-				}),
-			huh.NewText().
-				CharLimit(300).
-				Key("message").
-				Description("Mailbox:").
-				Placeholder("Enter message here").
-				Validate(func(str string) error {
-					if str == "" {
-						return fmt.Errorf("Message required, try again")
+// newForm builds the contact form, with challenge asked before the message
+// is sent when it isn't nil.
+func newForm(challenge *abuse.Challenge) *huh.Form {
+	fields := []huh.Field{
+		huh.NewInput().
+			Title("CodeDragon Mailer").
+			Description("Full Name: ").
+			Placeholder("Enter your full name here").
+			Validate(func(str string) error {
+				if str == "" {
+					return fmt.Errorf("name required, try again")
+				}
+				return nil
+			}).
+			Key("name"),
+		huh.NewInput().
+			Description("Email: ").
+			Placeholder("Enter your email here").
+			Key("email").
+			Validate(func(str string) error {
+				re := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+				if str != "" {
+					if !re.MatchString(str) {
+						return fmt.Errorf("invalid Email, try again")
 					}
-					return nil
-				}).
-				Lines(5),
-			huh.NewConfirm().
-				Key("done").
-				Title("Send Message?").
-				Affirmative("Yes!").
-				Negative("Cancel"),
-		),
-	)
+				} else {
+					return fmt.Errorf("email required, try again")
+				}
+				return nil
+			}),
+		huh.NewText().
+			CharLimit(300).
+			Key("message").
+			Description("Mailbox:").
+			Placeholder("Enter message here").
+			Validate(func(str string) error {
+				if str == "" {
+					return fmt.Errorf("Message required, try again")
+				}
+				return nil
+			}).
+			Lines(5),
+	}
+	if challenge != nil {
+		fields = append(fields, huh.NewInput().
+			Description(challenge.Question).
+			Placeholder("Prove you're not a robot").
+			Key("challenge").
+			Validate(func(str string) error {
+				if !challenge.Check(str) {
+					return fmt.Errorf("not quite, try again")
+				}
+				return nil
+			}))
+	}
+	fields = append(fields, huh.NewConfirm().
+		Key("done").
+		Title("Send Message?").
+		Affirmative("Yes!").
+		Negative("Cancel"))
+	return huh.NewForm(huh.NewGroup(fields...))
 }
 
-func NewContactModel(width int, height int) *ContactModel {
-	contactform := newForm()
-	return &ContactModel{
-		Width:      width,
-		Height:     height,
-		Help:       help.New(),
-		KeyMap:     ContactKeyMap{},
-		Form:       contactform,
-		EmailSent:  false,
-		EmailError: nil,
+// newForm builds a fresh form, with a new challenge for visitors without
+// a key.
+func (m *ContactModel) newForm() *huh.Form {
+	var challenge *abuse.Challenge
+	if m.Challenge && m.Visitor.KeyFingerprint == "" {
+		challenge = abuse.NewChallenge()
+	}
+	form := newForm(challenge)
+	if m.th != nil {
+		form.WithTheme(formTheme(m.th))
+	}
+	if m.Width > 0 {
+		form.WithWidth(min(m.Width, maxContentWidth)).WithHeight(m.Height - 1)
 	}
+	return form
+}
+
+// formTheme is huh's Charm theme drawn by th's renderer.
+func formTheme(th *theme.Theme) *huh.Theme {
+	t := huh.ThemeCharm()
+	th.Restyle(t)
+	return t
+}
+
+func (m *ContactModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.Form.WithTheme(formTheme(th))
+	return nil
+}
+
+func NewContactModel(width int, height int, visitor Visitor, opts ContactOptions) *ContactModel {
+	m := &ContactModel{
+		Width:          width,
+		Height:         height,
+		Visitor:        visitor,
+		ContactOptions: opts,
+		Help:           help.New(),
+		KeyMap:         ContactKeyMap{},
+		EmailSent:      false,
+		EmailError:     nil,
+	}
+	m.Form = m.newForm()
+	return m
 }
 
 type ContactKeyMap struct{}
 
 func (k ContactKeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
-		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Back to Menu")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
 	}
 }
 
@@ -141,12 +384,28 @@ func (k ContactKeyMap) FullHelp() [][]key.Binding {
 func (m *ContactModel) updateDimensions(width, height int) {
 	m.Width = width
 	m.Height = height
+	m.Help.Width = width
+	// The form's key help takes the last row.
+	m.Form.WithWidth(min(width, maxContentWidth)).WithHeight(height - 1)
 }
 
+// Init starts every visit with an empty form.
 func (m *ContactModel) Init() tea.Cmd {
+	m.Form = m.newForm()
+	m.EmailSent = false
+	m.EmailQueued = false
+	m.EmailError = nil
+	m.RetryAfter = 0
+	m.Rejected = ""
+	m.Submitted = false
 	return tea.Batch(tea.SetWindowTitle("Contact Me"), m.Form.Init())
 }
 
+// CapturesKey keeps backspace for the form's fields.
+func (m *ContactModel) CapturesKey(msg tea.KeyMsg) bool {
+	return msg.String() == "backspace"
+}
+
 func (m *ContactModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 	var (
 		cmd  tea.Cmd
@@ -156,7 +415,10 @@ func (m *ContactModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 	switch msg := msg.(type) {
 	case EmailSentMsg:
 		m.EmailSent = msg.success
+		m.EmailQueued = msg.queued
 		m.EmailError = msg.err
+		m.RetryAfter = msg.retryAfter
+		m.Rejected = msg.rejected
 		return m, nil
 
 	case tea.KeyMsg:
@@ -165,11 +427,6 @@ func (m *ContactModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 			return m, tea.Quit
 		case "ctrl+z":
 			return m, tea.Suspend
-		case "esc":
-			m.Form = newForm()
-			m.EmailSent = false
-			m.EmailError = nil
-			return GetMenuModel(m.Width, m.Height), tea.Batch(cmd, tea.SetWindowTitle("Dragon's Lair"))
 		case " ":
 			return m, cmd
 		}
@@ -185,15 +442,15 @@ func (m *ContactModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
-	if m.Form.State == huh.StateCompleted {
+	if m.Form.State == huh.StateCompleted && !m.Submitted {
+		m.Submitted = true
 		if m.Form.GetBool("done") {
 			m.FeedbackMsg = FeedbackMsg{
 				email:   m.Form.GetString("email"),
 				name:    m.Form.GetString("name"),
 				message: m.Form.GetString("message"),
 			}
-			// Send the email
-			cmds = append(cmds, sendEmail(m.FeedbackMsg.name, m.FeedbackMsg.email, m.FeedbackMsg.message))
+			cmds = append(cmds, submitMessage(m.ContactOptions, m.Visitor, m.FeedbackMsg))
 		}
 	}
 
@@ -205,27 +462,42 @@ func (m *ContactModel) View() string {
 	case huh.StateCompleted:
 		var s string
 
-		// Show error if email failed
-		if m.EmailError != nil {
-			s = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("196")).
+		if m.RetryAfter > 0 {
+			s = m.th.Style().
+				Align(lipgloss.Center, lipgloss.Center).
+				Render(fmt.Sprintf("\n Thanks %s, I've got plenty of your messages for now!\n\nYou can send another one in %s.\n",
+					m.th.Rainbow(m.th.Style(), m.FeedbackMsg.name), formatWait(m.RetryAfter)))
+		} else if m.Rejected != "" {
+			s = m.th.Style().
+				Foreground(m.th.Error).
+				Align(lipgloss.Center, lipgloss.Center).
+				Render(fmt.Sprintf("\n Sorry, your message wasn't sent: %s.\n", m.Rejected))
+		} else if m.EmailError != nil {
+			// Show error if email failed
+			s = m.th.Style().
+				Foreground(m.th.Error).
 				Align(lipgloss.Center, lipgloss.Center).
 				Render(fmt.Sprintf("\n Error sending email: %v\n\nPlease try again later.\n", m.EmailError))
+		} else if m.EmailQueued {
+			s = m.th.Style().
+				Align(lipgloss.Center, lipgloss.Center).
+				Render(fmt.Sprintf("\n Hey %s, your message is queued and will be delivered shortly!\n",
+					m.th.Rainbow(m.th.Style(), m.FeedbackMsg.name)))
 		} else if m.EmailSent {
 			// Show success message
-			s = lipgloss.NewStyle().
+			s = m.th.Style().
 				Align(lipgloss.Center, lipgloss.Center).
 				Render(fmt.Sprintf("\n Hey %s, your message was delivered successfully!\n",
-					utils.Rainbow(lipgloss.NewStyle(), m.FeedbackMsg.name, utils.Blends)))
+					m.th.Rainbow(m.th.Style(), m.FeedbackMsg.name)))
 		} else {
 			// Sending in progress
-			s = lipgloss.NewStyle().
+			s = m.th.Style().
 				Align(lipgloss.Center, lipgloss.Center).
 				Render("\nSending email...\n")
 		}
 
 		hlp := fmt.Sprintf("\n%s", m.Help.View(m.KeyMap))
-		finalRender := fmt.Sprintf("\n%s\n\n%s", utils.Banner.Bold(true).Italic(true).Render(s), hlp)
+		finalRender := fmt.Sprintf("\n%s\n\n%s", m.th.Style().Padding(1).Bold(true).Italic(true).Render(s), hlp)
 		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, finalRender)
 
 	default:
@@ -0,0 +1,51 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RateLimitStore keeps rate limit counters across restarts.
+type RateLimitStore interface {
+	HitRateLimit(ctx context.Context, keys []string, limit int, window time.Duration) (time.Duration, error)
+}
+
+// SubmissionLimit caps how many contact messages a visitor can send per
+// window, counted both per address and per key so that neither reconnecting
+// nor switching keys gets around it.
+type SubmissionLimit struct {
+	Store  RateLimitStore
+	Limit  int
+	Window time.Duration
+}
+
+// Allow counts a submission by visitor and returns how long they have to wait
+// if it is over the limit. A nil limit allows everything.
+func (l *SubmissionLimit) Allow(ctx context.Context, visitor Visitor) (time.Duration, error) {
+	if l == nil || l.Store == nil || l.Limit <= 0 {
+		return 0, nil
+	}
+	keys := []string{"contact:ip:" + visitor.IP()}
+	if visitor.KeyFingerprint != "" {
+		keys = append(keys, "contact:key:"+visitor.KeyFingerprint)
+	}
+	return l.Store.HitRateLimit(ctx, keys, l.Limit, l.Window)
+}
+
+// formatWait rounds a cooldown up to something friendly to read.
+func formatWait(d time.Duration) string {
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	switch {
+	case minutes <= 1:
+		return "a minute"
+	case minutes < 60:
+		return fmt.Sprintf("%d minutes", minutes)
+	default:
+		h := int(d.Round(time.Hour).Hours())
+		if h == 1 {
+			return "an hour"
+		}
+		return fmt.Sprintf("%d hours", h)
+	}
+}
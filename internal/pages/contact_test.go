@@ -0,0 +1,106 @@
+package pages
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"DragonTUI/internal/abuse"
+	"DragonTUI/internal/db"
+	"DragonTUI/internal/mail"
+)
+
+type failingStore struct{}
+
+func (failingStore) CreateMessage(context.Context, *db.Message) error {
+	return errors.New("disk full")
+}
+
+func (failingStore) SetMessageDeliveryStatus(context.Context, int64, db.DeliveryStatus, string) error {
+	return nil
+}
+
+func (failingStore) SetUserName(context.Context, int64, string) error { return nil }
+
+type countingMailer struct{ sent int }
+
+func (m *countingMailer) Send(context.Context, *mail.Message) error {
+	m.sent++
+	return nil
+}
+
+type countingQueue struct{ queued int }
+
+func (q *countingQueue) Enqueue(context.Context, *mail.Message, int64) error {
+	q.queued++
+	return nil
+}
+
+func quarantineAll() abuse.Filter {
+	return abuse.FilterFunc(func(context.Context, abuse.Submission) (abuse.Verdict, error) {
+		return abuse.Verdict{Action: abuse.Quarantine, Reason: "looks like spam"}, nil
+	})
+}
+
+func TestQuarantinedMessageIsNeverEmailed(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		store MessageStore
+	}{
+		{"store fails", failingStore{}},
+		{"no store", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mailer, queue := &countingMailer{}, &countingQueue{}
+			opts := ContactOptions{Store: tc.store, Mailer: mailer, Queue: queue, Filter: quarantineAll()}
+			feedback := FeedbackMsg{name: "Eve", email: "eve@example.com", message: "cheap pills"}
+
+			result := submitMessage(opts, Visitor{RemoteAddr: "192.0.2.1:22"}, feedback)().(EmailSentMsg)
+
+			if mailer.sent != 0 || queue.queued != 0 {
+				t.Fatalf("quarantined message was delivered: %d sent, %d queued", mailer.sent, queue.queued)
+			}
+			if result.err == nil || result.outcome() != "failed" {
+				t.Fatalf("unsaved quarantined message reported as %q, want failed", result.outcome())
+			}
+		})
+	}
+}
+
+func TestQuarantinedMessageIsStored(t *testing.T) {
+	store := &memoryStore{}
+	mailer := &countingMailer{}
+	opts := ContactOptions{Store: store, Mailer: mailer, Filter: quarantineAll()}
+
+	result := submitMessage(opts, Visitor{}, FeedbackMsg{name: "Eve", email: "eve@example.com", message: "hi"})().(EmailSentMsg)
+
+	if mailer.sent != 0 {
+		t.Fatalf("quarantined message was emailed")
+	}
+	if result.outcome() != "quarantined" {
+		t.Fatalf("outcome %q, want quarantined", result.outcome())
+	}
+	if len(store.messages) != 1 || store.messages[0].DeliveryStatus != db.DeliveryQuarantined {
+		t.Fatalf("stored %+v, want one quarantined message", store.messages)
+	}
+}
+
+type memoryStore struct {
+	messages []db.Message
+}
+
+func (s *memoryStore) CreateMessage(_ context.Context, m *db.Message) error {
+	m.ID = int64(len(s.messages) + 1)
+	m.CreatedAt = time.Now()
+	s.messages = append(s.messages, *m)
+	return nil
+}
+
+func (s *memoryStore) SetMessageDeliveryStatus(_ context.Context, id int64, status db.DeliveryStatus, deliveryErr string) error {
+	s.messages[id-1].DeliveryStatus = status
+	s.messages[id-1].DeliveryError = deliveryErr
+	return nil
+}
+
+func (s *memoryStore) SetUserName(context.Context, int64, string) error { return nil }
@@ -0,0 +1,28 @@
+package pages
+
+import (
+	"context"
+
+	"DragonTUI/internal/content"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ContentUpdatedMsg is sent to every session when a content store or
+// library publishes new content.
+type ContentUpdatedMsg struct {
+	Source content.Notifier
+}
+
+// WaitForContent returns a command that resolves once source changes, or
+// never if ctx ends first. Re-issue it after every ContentUpdatedMsg.
+func WaitForContent(ctx context.Context, source content.Notifier) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-source.Changed():
+			return ContentUpdatedMsg{Source: source}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
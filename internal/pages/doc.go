@@ -0,0 +1,310 @@
+package pages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/theme"
+	"DragonTUI/internal/utils"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+)
+
+// DocModel shows a single document from the library, selected by the "slug"
+// navigation parameter, with a table of contents built from its headings.
+type DocModel struct {
+	Width    int
+	Height   int
+	Help     help.Model
+	KeyMap   DocKeyMap
+	Library  *content.Library
+	Doc      *content.Document
+	Viewport viewport.Model
+	TOC      list.Model
+	ShowTOC  bool
+
+	// rendered is Doc in the session's glamour style.
+	rendered string
+	find     finder
+	themed
+}
+
+type tocItem struct {
+	heading content.Heading
+}
+
+func (i tocItem) Title() string {
+	return strings.Repeat("  ", utils.Max(0, i.heading.Level-1)) + i.heading.Text
+}
+func (i tocItem) Description() string { return "" }
+func (i tocItem) FilterValue() string { return i.heading.Text }
+
+func NewDocModel(width, height int, library *content.Library) *DocModel {
+	toc := list.New(nil, newTOCDelegate(list.NewDefaultDelegate()), 0, 0)
+	toc.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	toc.Title = "Contents"
+	toc.SetShowStatusBar(false)
+	toc.SetShowHelp(false)
+
+	return &DocModel{
+		Width:    width,
+		Height:   height,
+		Help:     help.New(),
+		KeyMap:   DocKeyMap{},
+		Library:  library,
+		Viewport: viewport.New(width, height),
+		TOC:      toc,
+		find:     newFinder(),
+	}
+}
+
+// newTOCDelegate packs the table of contents one heading to a line.
+func newTOCDelegate(d list.DefaultDelegate) list.DefaultDelegate {
+	d.ShowDescription = false
+	d.SetSpacing(0)
+	return d
+}
+
+// SetTheme re-renders the open document in th's glamour style.
+func (m *DocModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.TOC.SetDelegate(newTOCDelegate(newListDelegate(th)))
+	if m.Doc != nil {
+		m.open(m.Doc.Slug)
+	}
+	return nil
+}
+
+func (m *DocModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DocModel) Enter(params map[string]string) tea.Cmd {
+	slug := params["slug"]
+	if m.Doc == nil || m.Doc.Slug != slug {
+		m.find.clear()
+		m.open(slug)
+		m.Viewport.GotoTop()
+	}
+	m.ShowTOC = false
+	if q := params["q"]; q != "" {
+		m.find.clear()
+		m.find.query = q
+		m.refreshContent()
+		n, _ := strconv.Atoi(params["match"])
+		m.gotoHit(m.find.seek(n))
+	}
+	return tea.SetWindowTitle(m.title())
+}
+
+func (m *DocModel) title() string {
+	if m.Doc == nil {
+		return "Writing"
+	}
+	return m.Doc.Title
+}
+
+// open loads slug from the library, keeping the scroll position when the
+// same document is reopened after a reload.
+func (m *DocModel) open(slug string) {
+	doc, ok := m.Library.Document(slug)
+	if !ok {
+		m.Doc = nil
+		m.Viewport.SetContent(fmt.Sprintf("\nDocument %q not found.\n", slug))
+		m.TOC.SetItems(nil)
+		return
+	}
+	rendered, headings, err := doc.RenderedIn(m.th.Markdown(), m.th.Profile(), m.Width)
+	if err != nil {
+		log.Error("Could not render document", "slug", slug, "style", m.th.Markdown(), "err", err)
+		rendered, headings = doc.Rendered, doc.Headings
+	}
+	m.Doc, m.rendered = doc, rendered
+	m.refreshContent()
+
+	var items []list.Item
+	for _, h := range headings {
+		if h.Line >= 0 {
+			items = append(items, tocItem{heading: h})
+		}
+	}
+	m.TOC.SetItems(items)
+}
+
+// refreshContent redraws the document, highlighting any active search,
+// without moving the scroll position.
+func (m *DocModel) refreshContent() {
+	if m.Doc == nil {
+		return
+	}
+	rendered := m.rendered
+	if m.find.searching() {
+		rendered = m.find.apply(m.Doc.Markdown, rendered, m.th.Markdown(), m.th.Profile(), m.Width)
+	}
+	offset := m.Viewport.YOffset
+	m.Viewport.SetContent(rendered)
+	m.Viewport.SetYOffset(offset)
+}
+
+func (m *DocModel) gotoHit(line int, ok bool) {
+	if ok {
+		m.Viewport.SetYOffset(line)
+	}
+}
+
+// CapturesKey keeps every key while the visitor filters the contents or
+// types a search, and esc for closing the contents or clearing the search.
+func (m *DocModel) CapturesKey(msg tea.KeyMsg) bool {
+	if m.find.typing || m.ShowTOC && m.TOC.FilterState() == list.Filtering {
+		return true
+	}
+	return (m.ShowTOC || m.find.searching()) && isBackKey(msg)
+}
+
+func (m *DocModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		rewrap := msg.Width != m.Width
+		m.updateDimensions(msg.Width, msg.Height)
+		if rewrap && m.Doc != nil {
+			m.open(m.Doc.Slug)
+		}
+		return m, nil
+
+	case ContentUpdatedMsg:
+		if msg.Source == content.Notifier(m.Library) && m.Doc != nil {
+			m.open(m.Doc.Slug)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.ShowTOC && m.TOC.FilterState() == list.Filtering {
+			break
+		}
+		if m.find.typing {
+			submitted, cmd := m.find.update(msg)
+			if submitted {
+				m.refreshContent()
+				m.gotoHit(m.find.line())
+			}
+			return m, cmd
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "esc", "backspace":
+			if m.ShowTOC {
+				m.ShowTOC = false
+				return m, nil
+			}
+			m.find.clear()
+			m.refreshContent()
+			return m, nil
+		case "t":
+			m.ShowTOC = !m.ShowTOC && len(m.TOC.Items()) > 0
+			return m, nil
+		case "/":
+			if m.ShowTOC || m.Doc == nil {
+				break
+			}
+			return m, m.find.start()
+		case "n", "N":
+			if m.ShowTOC {
+				break
+			}
+			if msg.String() == "n" {
+				m.gotoHit(m.find.next())
+			} else {
+				m.gotoHit(m.find.prev())
+			}
+			return m, nil
+		case "enter":
+			if !m.ShowTOC {
+				break
+			}
+			if i, ok := m.TOC.SelectedItem().(tocItem); ok {
+				m.Viewport.SetYOffset(i.heading.Line)
+			}
+			m.ShowTOC = false
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.ShowTOC {
+		m.TOC, cmd = m.TOC.Update(msg)
+	} else {
+		m.Viewport, cmd = m.Viewport.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *DocModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+}
+
+func (m *DocModel) headerView() string {
+	s := titleStyle(m.th).Render(m.th.Rainbow(m.th.Style().Bold(true), m.title()))
+	line := m.th.Rule(utils.Max(0, m.Viewport.Width-lipgloss.Width(s)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, s, m.th.Style().Foreground(m.th.Error).Render(line))
+}
+
+func (m *DocModel) footerView() string {
+	info := infoStyle(m.th).Render(fmt.Sprintf("%3.f%%", m.Viewport.ScrollPercent()*100))
+	line := m.th.Rule(utils.Max(0, m.Viewport.Width-lipgloss.Width(info)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, m.th.Style().Foreground(m.th.Success).Render(line), m.th.Style().Foreground(m.th.Highlight).Bold(true).Render(info))
+}
+
+func (m *DocModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+	hlp := helpView(m.Help, m.KeyMap)
+
+	m.Viewport.Width = m.Width
+	verticalMarginHeight := lipgloss.Height(m.headerView()) + lipgloss.Height(m.footerView()) + lipgloss.Height(hlp)
+	status := m.find.view(m.th)
+	if status != "" {
+		verticalMarginHeight += lipgloss.Height(status)
+		hlp = status + "\n" + hlp
+	}
+	m.Viewport.Height = utils.Max(0, m.Height-verticalMarginHeight)
+
+	body := m.Viewport.View()
+	if m.ShowTOC {
+		m.TOC.SetSize(utils.Max(0, m.Width/2), m.Viewport.Height)
+		body = lipgloss.Place(m.Width, m.Viewport.Height, lipgloss.Left, lipgloss.Top, m.TOC.View())
+	}
+
+	s := fmt.Sprintf("%s\n%s\n%s\n%s", m.headerView(), body, m.footerView(), hlp)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type DocKeyMap struct{}
+
+func (k DocKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("↑/k", "move up")),
+		key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("↓/j", "move down")),
+		key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "contents")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		key.NewBinding(key.WithKeys("n", "N"), key.WithHelp("n/N", "next/prev match")),
+		key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc", "back")),
+		key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "forward")),
+	}
+}
+
+func (k DocKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,173 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type DocsModel struct {
+	Width   int
+	Height  int
+	Help    help.Model
+	KeyMap  DocsKeyMap
+	List    list.Model
+	Library *content.Library
+	themed
+}
+
+type docItem struct {
+	doc *content.Document
+}
+
+func (i docItem) Title() string { return i.doc.Title }
+
+func (i docItem) Description() string {
+	var parts []string
+	if !i.doc.Date.IsZero() {
+		parts = append(parts, i.doc.Date.Format("2006-01-02"))
+	}
+	if len(i.doc.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(i.doc.Tags, " #"))
+	}
+	if i.doc.Summary != "" {
+		parts = append(parts, i.doc.Summary)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (i docItem) FilterValue() string {
+	return i.doc.Title + " " + strings.Join(i.doc.Tags, " ") + " " + i.doc.Summary
+}
+
+func NewDocsModel(width, height int, library *content.Library) *DocsModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	l.Title = "Writing"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+
+	m := &DocsModel{
+		Width:   width,
+		Height:  height,
+		Help:    help.New(),
+		KeyMap:  DocsKeyMap{},
+		List:    l,
+		Library: library,
+	}
+	m.updateDimensions(width, height)
+	m.syncDocuments()
+	return m
+}
+
+func (m *DocsModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.List.SetDelegate(newListDelegate(th))
+	return nil
+}
+
+func (m *DocsModel) Init() tea.Cmd {
+	return tea.SetWindowTitle("Writing")
+}
+
+func (m *DocsModel) syncDocuments() tea.Cmd {
+	docs := m.Library.Documents()
+	items := make([]list.Item, len(docs))
+	for i, d := range docs {
+		items[i] = docItem{doc: d}
+	}
+	return m.List.SetItems(items)
+}
+
+// CapturesKey keeps every key while the visitor types a filter, and esc
+// for clearing it once applied.
+func (m *DocsModel) CapturesKey(msg tea.KeyMsg) bool {
+	switch m.List.FilterState() {
+	case list.Filtering:
+		return true
+	case list.FilterApplied:
+		return isBackKey(msg)
+	}
+	return false
+}
+
+func (m *DocsModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+
+	case ContentUpdatedMsg:
+		if msg.Source == content.Notifier(m.Library) {
+			return m, m.syncDocuments()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.List.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "esc", "backspace":
+			m.List.ResetFilter()
+			return m, nil
+		case "enter":
+			if i, ok := m.List.SelectedItem().(docItem); ok {
+				return m, Navigate(RouteDoc, map[string]string{"slug": i.doc.Slug})
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.List, cmd = m.List.Update(msg)
+	return m, cmd
+}
+
+func (m *DocsModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.List.SetSize(listSize(width, height, 2))
+}
+
+func (m *DocsModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+
+	body := m.List.View()
+	if len(m.List.Items()) == 0 {
+		body = fmt.Sprintf("\nNo documents found in %s\n", m.Library.Dir())
+	}
+	if err := m.Library.Err(); err != nil {
+		body += "\n" + m.th.Style().Foreground(m.th.Error).Render(err.Error())
+	}
+	s := fmt.Sprintf("%s\n%s", body, m.Help.View(m.KeyMap))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type DocsKeyMap struct{}
+
+func (k DocsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}
+}
+
+func (k DocsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
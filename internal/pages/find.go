@@ -0,0 +1,136 @@
+package pages
+
+import (
+	"fmt"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/theme"
+	"DragonTUI/internal/utils"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
+)
+
+// finder is the "/" search mode shared by the content pages. Matches are
+// found in the markdown source, as the search page finds them, so the nth
+// result there is the nth hit here, and highlighted in the rendered view.
+type finder struct {
+	input   textinput.Model
+	typing  bool
+	query   string
+	hits    []int
+	current int
+	// missing is the match the page was opened at, counting from one, if
+	// the page has fewer matches than that.
+	missing int
+}
+
+func newFinder() finder {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "search"
+	ti.CharLimit = 64
+	return finder{input: ti}
+}
+
+func (f *finder) start() tea.Cmd {
+	f.typing = true
+	f.input.SetValue(f.query)
+	f.input.CursorEnd()
+	return f.input.Focus()
+}
+
+// update feeds a key to the search prompt while typing. It reports whether
+// the query was submitted.
+func (f *finder) update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		f.typing = false
+		f.input.Blur()
+		if q := f.input.Value(); q != f.query {
+			f.query = q
+			f.current = 0
+		}
+		f.missing = 0
+		return true, nil
+	case "esc":
+		f.typing = false
+		f.input.Blur()
+		return false, nil
+	}
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return false, cmd
+}
+
+// apply searches markdown for the current query and returns rendered, its
+// rendering in style for profile at width, with the matches highlighted.
+func (f *finder) apply(markdown, rendered, style string, profile termenv.Profile, width int) string {
+	hits, err := content.MatchLines(markdown, f.query, style, profile, width)
+	if err != nil {
+		log.Error("Could not find matches", "query", f.query, "err", err)
+	}
+	f.hits = hits
+	f.current = min(f.current, utils.Max(0, len(f.hits)-1))
+	return content.Highlight(rendered, f.query)
+}
+
+func (f *finder) clear() {
+	f.query = ""
+	f.hits = nil
+	f.current = 0
+	f.missing = 0
+}
+
+func (f *finder) searching() bool {
+	return f.query != ""
+}
+
+// line returns the rendered line of the current hit.
+func (f *finder) line() (int, bool) {
+	if len(f.hits) == 0 {
+		return 0, false
+	}
+	return f.hits[f.current], true
+}
+
+// seek selects hit n. If there is no such hit it selects none, and the
+// status line says so.
+func (f *finder) seek(n int) (int, bool) {
+	f.missing = 0
+	if n < 0 || n >= len(f.hits) {
+		f.missing = n + 1
+		return 0, false
+	}
+	f.current = n
+	return f.hits[n], true
+}
+
+// next and prev move to the following or preceding hit, wrapping around
+// either end.
+func (f *finder) next() (int, bool) { return f.step(1) }
+func (f *finder) prev() (int, bool) { return f.step(-1) }
+
+func (f *finder) step(by int) (int, bool) {
+	if len(f.hits) == 0 {
+		return 0, false
+	}
+	return f.seek((f.current + by + len(f.hits)) % len(f.hits))
+}
+
+func (f *finder) view(th *theme.Theme) string {
+	switch {
+	case f.typing:
+		return f.input.View()
+	case !f.searching():
+		return ""
+	case len(f.hits) == 0:
+		return th.Style().Foreground(th.Error).Render(fmt.Sprintf("/%s: no matches", f.query))
+	case f.missing > 0:
+		return th.Style().Foreground(th.Error).Render(fmt.Sprintf("/%s: match %d not found, %d on this page", f.query, f.missing, len(f.hits)))
+	default:
+		return fmt.Sprintf("/%s: match %d of %d", f.query, f.current+1, len(f.hits))
+	}
+}
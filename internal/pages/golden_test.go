@@ -0,0 +1,170 @@
+package pages
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+const goldenResume = `# Dragon
+
+Builds terminal apps and the servers behind them.
+
+## Experience
+
+- Writes Go for a living
+- Keeps a lair on the internet
+`
+
+const goldenDoc = `# Needles
+
+See [the needle guide](https://example.com/needle) first.
+
+This sentence runs long enough that the words threaded needle sit across a wrapped line.
+
+A *needle* again.
+`
+
+// goldenSizes are the window sizes pages are checked at: the smallest
+// window pages lay themselves out for, one in the compact breakpoint and
+// one in the regular breakpoint.
+var goldenSizes = []struct {
+	name          string
+	width, height int
+}{
+	{"min", MinWidth, MinHeight},
+	{"compact", 60, 24},
+	{"wide", 120, 40},
+}
+
+// newGoldenRouter registers the pages under test with fixed content, a
+// fixed visitor and no database.
+func newGoldenRouter(t *testing.T, width, height int) *Router {
+	t.Helper()
+	resume := content.NewStore(func() (string, error) { return goldenResume, nil }, theme.Default().Markdown())
+	if err := resume.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "needles.md"), []byte(goldenDoc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	library := content.NewLibrary(dir, theme.Default().Markdown())
+	if err := library.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	index := content.NewIndex()
+	index.Rebuild(resume, library)
+	visitor := Visitor{User: "visitor", Name: "Visitor"}
+
+	r := NewRouter(width, height, theme.Default())
+	r.Register(RouteMenu, func(w, h int) Page { return NewMenuModel(w, h, visitor, false, nil, nil) })
+	r.Register(RouteAbout, func(w, h int) Page { return NewAboutModel(w, h, resume) })
+	r.Register(RouteContact, func(w, h int) Page { return NewContactModel(w, h, visitor, ContactOptions{}) })
+	r.Register(RouteSearch, func(w, h int) Page { return NewSearchModel(w, h, index) })
+	r.Register(RouteDoc, func(w, h int) Page { return NewDocModel(w, h, library) })
+	if _, err := r.Start(RouteMenu); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// view draws the session the way the app does, falling back to the
+// too-small screen below MinWidth by MinHeight.
+func view(r *Router, width, height int) string {
+	if TooSmall(width, height) {
+		return RenderTooSmall(r.Theme(), width, height)
+	}
+	return r.Current().View()
+}
+
+// assertFits fails if view runs past a width by height window.
+func assertFits(t *testing.T, view string, width, height int) {
+	t.Helper()
+	lines := strings.Split(view, "\n")
+	if len(lines) > height {
+		t.Errorf("view is %d rows, the window %d", len(lines), height)
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w > width {
+			t.Errorf("row %d is %d columns, the window %d: %q", i+1, w, width, line)
+		}
+	}
+}
+
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from %s:\n%s", name, path, got)
+	}
+}
+
+func TestPagesGolden(t *testing.T) {
+	pages := []struct {
+		name   string
+		route  Route
+		params map[string]string
+		keys   string
+	}{
+		{name: "menu", route: RouteMenu},
+		{name: "about", route: RouteAbout},
+		{name: "contact", route: RouteContact},
+		{name: "search", route: RouteSearch, keys: "needle"},
+		{name: "doc", route: RouteDoc, params: map[string]string{"slug": "needles"}},
+	}
+	for _, p := range pages {
+		for _, size := range goldenSizes {
+			name := p.name + "-" + size.name
+			t.Run(name, func(t *testing.T) {
+				r := newGoldenRouter(t, size.width, size.height)
+				if p.route != RouteMenu {
+					r.Update(NavigateMsg{Route: p.route, Params: p.params})
+				}
+				r.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+				for _, c := range p.keys {
+					r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{c}})
+				}
+				// Searches run as commands; deliver the last one's results.
+				if search, ok := r.Current().(*SearchModel); ok {
+					r.Update(search.search()())
+				}
+				got := view(r, size.width, size.height)
+				assertFits(t, got, size.width, size.height)
+				assertGolden(t, name, got)
+			})
+		}
+	}
+}
+
+func TestTooSmallGolden(t *testing.T) {
+	const width, height = MinWidth - 1, MinHeight - 1
+	r := newGoldenRouter(t, width, height)
+	r.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	got := view(r, width, height)
+	assertFits(t, got, width, height)
+	assertGolden(t, "too-small", got)
+}
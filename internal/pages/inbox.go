@@ -0,0 +1,347 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"DragonTUI/internal/db"
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// InboxStore is the part of the database the admin inbox reads and edits.
+type InboxStore interface {
+	ListMessages(ctx context.Context) ([]db.Message, error)
+	MarkMessageRead(ctx context.Context, id int64, read bool) error
+	DeleteMessage(ctx context.Context, id int64) error
+	OutboxCounts(ctx context.Context) (db.OutboxCounts, error)
+	Block(ctx context.Context, kind db.BlockKind, value, reason string) error
+}
+
+type InboxModel struct {
+	Width    int
+	Height   int
+	Help     help.Model
+	KeyMap   InboxKeyMap
+	List     list.Model
+	Viewport viewport.Model
+	Store    InboxStore
+	// Queue delivers quarantined messages once they are approved.
+	Queue   MailQueue
+	Audit   Auditor
+	Reading *db.Message
+	Err     error
+	themed
+}
+
+type messageItem struct {
+	msg db.Message
+}
+
+func (i messageItem) Title() string {
+	marker := "  "
+	if !i.msg.Read {
+		marker = "● "
+	}
+	return fmt.Sprintf("%s%s <%s>", marker, i.msg.Name, i.msg.Email)
+}
+
+func (i messageItem) Description() string {
+	return fmt.Sprintf("%s · %s", i.msg.CreatedAt.Local().Format("2006-01-02 15:04"), i.msg.DeliveryStatus)
+}
+
+func (i messageItem) FilterValue() string { return i.msg.Name + " " + i.msg.Email }
+
+type inboxLoadedMsg struct {
+	messages []db.Message
+	counts   db.OutboxCounts
+	err      error
+}
+
+type inboxChangedMsg struct {
+	err error
+}
+
+func NewInboxModel(width, height int, store InboxStore, queue MailQueue, audit Auditor) *InboxModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	l.Title = "Inbox"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+
+	m := &InboxModel{
+		Width:    width,
+		Height:   height,
+		Help:     help.New(),
+		KeyMap:   InboxKeyMap{},
+		List:     l,
+		Viewport: viewport.New(0, 0),
+		Store:    store,
+		Queue:    queue,
+		Audit:    audit,
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *InboxModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.List.SetDelegate(newListDelegate(th))
+	if m.Reading != nil {
+		m.Viewport.SetContent(m.renderMessage(*m.Reading))
+	}
+	return nil
+}
+
+func (m *InboxModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Inbox"), m.load())
+}
+
+func (m *InboxModel) load() tea.Cmd {
+	store := m.Store
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		messages, err := store.ListMessages(ctx)
+		if err != nil {
+			return inboxLoadedMsg{err: err}
+		}
+		counts, err := store.OutboxCounts(ctx)
+		return inboxLoadedMsg{messages: messages, counts: counts, err: err}
+	}
+}
+
+func (m *InboxModel) change(fn func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return inboxChangedMsg{err: fn(ctx)}
+	}
+}
+
+// record audits an admin action; it is called from commands, off the UI
+// goroutine.
+func (m *InboxModel) record(action, detail string) {
+	if m.Audit != nil {
+		m.Audit.Record(action, detail)
+	}
+}
+
+func (m *InboxModel) selected() (db.Message, bool) {
+	i, ok := m.List.SelectedItem().(messageItem)
+	return i.msg, ok
+}
+
+// current is the message being read, or else the selected one.
+func (m *InboxModel) current() (db.Message, bool) {
+	if m.Reading != nil {
+		return *m.Reading, true
+	}
+	return m.selected()
+}
+
+// CapturesKey keeps esc for closing the message being read.
+func (m *InboxModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.Reading != nil && isBackKey(msg)
+}
+
+func (m *InboxModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+
+	case inboxLoadedMsg:
+		m.Err = msg.err
+		m.List.Title = fmt.Sprintf("Inbox · outbox: %d queued, %d sent, %d failed", msg.counts.Queued, msg.counts.Sent, msg.counts.Failed)
+		items := make([]list.Item, len(msg.messages))
+		for i, message := range msg.messages {
+			items[i] = messageItem{msg: message}
+		}
+		return m, m.List.SetItems(items)
+
+	case inboxChangedMsg:
+		m.Err = msg.err
+		return m, m.load()
+
+	case tea.KeyMsg:
+		if m.List.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "esc", "backspace":
+			m.Reading = nil
+			return m, nil
+		case "r":
+			return m, m.load()
+		case "enter":
+			if m.Reading != nil {
+				return m, nil
+			}
+			message, ok := m.selected()
+			if !ok {
+				return m, nil
+			}
+			m.Reading = &message
+			m.Viewport.SetContent(m.renderMessage(message))
+			m.Viewport.GotoTop()
+			if message.Read {
+				return m, nil
+			}
+			return m, m.change(func(ctx context.Context) error {
+				return m.Store.MarkMessageRead(ctx, message.ID, true)
+			})
+		case "m":
+			message, ok := m.selected()
+			if m.Reading != nil {
+				message, ok = *m.Reading, true
+			}
+			if !ok {
+				return m, nil
+			}
+			return m, m.change(func(ctx context.Context) error {
+				return m.Store.MarkMessageRead(ctx, message.ID, !message.Read)
+			})
+		case "a":
+			message, ok := m.current()
+			if !ok || message.DeliveryStatus != db.DeliveryQuarantined {
+				return m, nil
+			}
+			if m.Queue == nil {
+				m.Err = fmt.Errorf("mail delivery is not configured")
+				return m, nil
+			}
+			m.Reading = nil
+			return m, m.change(func(ctx context.Context) error {
+				if err := m.Queue.Enqueue(ctx, contactEmail(message.Name, message.Email, message.Body), message.ID); err != nil {
+					return err
+				}
+				m.record("admin.approve", fmt.Sprintf("message %d from %s", message.ID, message.Email))
+				return nil
+			})
+		case "b":
+			message, ok := m.current()
+			if !ok {
+				return m, nil
+			}
+			return m, m.change(func(ctx context.Context) error {
+				reason := fmt.Sprintf("blocked from the inbox, message %d", message.ID)
+				blocked := message.Email
+				if message.KeyFingerprint != "" {
+					if err := m.Store.Block(ctx, db.BlockKey, message.KeyFingerprint, reason); err != nil {
+						return err
+					}
+					blocked += " and " + message.KeyFingerprint
+				}
+				if err := m.Store.Block(ctx, db.BlockEmail, message.Email, reason); err != nil {
+					return err
+				}
+				m.record("admin.block", blocked)
+				return nil
+			})
+		case "d":
+			message, ok := m.selected()
+			if m.Reading != nil {
+				message, ok = *m.Reading, true
+			}
+			if !ok {
+				return m, nil
+			}
+			m.Reading = nil
+			return m, m.change(func(ctx context.Context) error {
+				if err := m.Store.DeleteMessage(ctx, message.ID); err != nil {
+					return err
+				}
+				m.record("admin.delete", fmt.Sprintf("message %d from %s", message.ID, message.Email))
+				return nil
+			})
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.Reading != nil {
+		m.Viewport, cmd = m.Viewport.Update(msg)
+	} else {
+		m.List, cmd = m.List.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *InboxModel) renderMessage(message db.Message) string {
+	label := m.th.Style().Bold(true).Foreground(m.th.Text)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s <%s>\n", label.Render("From:"), message.Name, message.Email)
+	fmt.Fprintf(&b, "%s %s\n", label.Render("Date:"), message.CreatedAt.Local().Format(time.RFC1123))
+	fmt.Fprintf(&b, "%s %s@%s\n", label.Render("SSH:"), message.SSHUser, message.RemoteAddr)
+	if message.KeyFingerprint != "" {
+		fmt.Fprintf(&b, "%s %s\n", label.Render("Key:"), message.KeyFingerprint)
+	}
+	status := string(message.DeliveryStatus)
+	if message.DeliveryError != "" {
+		status += " (" + message.DeliveryError + ")"
+	}
+	if message.DeliveryStatus == db.DeliveryQuarantined {
+		status += ", press a to approve"
+	}
+	fmt.Fprintf(&b, "%s %s\n\n", label.Render("Delivery:"), status)
+	b.WriteString(m.th.Style().Width(m.Viewport.Width).Render(message.Body))
+	return b.String()
+}
+
+func (m *InboxModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.List.SetSize(listSize(width, height, 2))
+	m.Viewport.Width, m.Viewport.Height = listSize(width, height, 2)
+	if m.Reading != nil {
+		m.Viewport.SetContent(m.renderMessage(*m.Reading))
+	}
+}
+
+func (m *InboxModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+
+	var body string
+	if m.Reading != nil {
+		body = m.Viewport.View()
+	} else {
+		body = m.List.View()
+	}
+	if m.Err != nil {
+		body += "\n" + m.th.Style().Foreground(m.th.Error).Render(m.Err.Error())
+	}
+	s := fmt.Sprintf("%s\n%s", body, m.Help.View(m.KeyMap))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type InboxKeyMap struct{}
+
+func (k InboxKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "read")),
+		key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "mark read/unread")),
+		key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+		key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "approve")),
+		key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "block sender")),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}
+}
+
+func (k InboxKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
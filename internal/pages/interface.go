@@ -1,9 +1,31 @@
 package pages
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"DragonTUI/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 type Page interface {
 	Init() tea.Cmd
 	Update(tea.Msg) (Page, tea.Cmd)
 	View() string
 }
+
+// Themed is implemented by pages drawn in the session's theme. The router
+// calls SetTheme when it creates the page and whenever the visitor switches
+// themes, including while the page is in the background.
+type Themed interface {
+	SetTheme(th *theme.Theme) tea.Cmd
+}
+
+// themed gives a page the session's theme. Pages that cache anything drawn
+// in it override SetTheme to redraw.
+type themed struct {
+	th *theme.Theme
+}
+
+func (t *themed) SetTheme(th *theme.Theme) tea.Cmd {
+	t.th = th
+	return nil
+}
@@ -0,0 +1,87 @@
+package pages
+
+import (
+	"fmt"
+
+	"DragonTUI/internal/theme"
+	"DragonTUI/internal/utils"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Pages lay themselves out for one of a few window sizes. Below MinWidth
+// by MinHeight nothing fits, and the session shows RenderTooSmall instead
+// of the page; below compactWidth by compactHeight pages drop decoration
+// such as the full logo.
+const (
+	MinWidth  = 40
+	MinHeight = 12
+
+	compactWidth  = 80
+	compactHeight = 30
+
+	// maxContentWidth caps how wide lists run on wide terminals.
+	maxContentWidth = 81
+)
+
+// Breakpoint is the class of window size a page lays itself out for.
+type Breakpoint int
+
+const (
+	BreakpointTiny Breakpoint = iota
+	BreakpointCompact
+	BreakpointRegular
+)
+
+// BreakpointFor classifies a width by height window.
+func BreakpointFor(width, height int) Breakpoint {
+	switch {
+	case width < MinWidth || height < MinHeight:
+		return BreakpointTiny
+	case width < compactWidth || height < compactHeight:
+		return BreakpointCompact
+	default:
+		return BreakpointRegular
+	}
+}
+
+// TooSmall reports whether a width by height window is too small for any
+// page.
+func TooSmall(width, height int) bool {
+	return BreakpointFor(width, height) == BreakpointTiny
+}
+
+// RenderTooSmall fills a window too small for pages with a request to
+// enlarge it.
+func RenderTooSmall(th *theme.Theme, width, height int) string {
+	text := fmt.Sprintf("Terminal too small\n\n%dx%d, need %dx%d\n\nctrl+c quits", width, height, MinWidth, MinHeight)
+	s := th.Style().Foreground(th.Text).Width(width).Align(lipgloss.Center).Render(text)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, s)
+}
+
+// logo is the menu banner that fits a width by height window. Below twice
+// MinHeight the small logo leaves too few rows for the menu.
+func logo(width, height int) string {
+	switch {
+	case BreakpointFor(width, height) == BreakpointRegular:
+		return utils.Logo
+	case width >= lipgloss.Width(utils.LogoSmall) && height >= 2*MinHeight:
+		return utils.LogoSmall
+	default:
+		return utils.LogoText
+	}
+}
+
+// listSize is the size of a list in a width by height window that leaves
+// reserved rows for the rest of the page.
+func listSize(width, height, reserved int) (int, int) {
+	return min(width, maxContentWidth), max(0, height-reserved)
+}
+
+// helpView is h's short help cut to its width. help.Model stops truncating
+// once there is no room left for its ellipsis, and long key maps then run
+// past the window.
+func helpView(h help.Model, keys help.KeyMap) string {
+	return lipgloss.NewStyle().MaxWidth(h.Width).Render(h.View(keys))
+}
@@ -1,9 +1,13 @@
 package pages
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"time"
 
-	"DragonTUI/internal/utils"
+	"DragonTUI/internal/db"
+	"DragonTUI/internal/theme"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -22,6 +26,9 @@ const (
 	MenuItemAbout
 	MenuItemContact
 	MenuItemGithub
+	MenuItemWriting
+	MenuItemSearch
+	MenuItemAdmin
 )
 
 func (m MenuItem) String() string {
@@ -32,11 +39,37 @@ func (m MenuItem) String() string {
 		return "Contact Me"
 	case MenuItemGithub:
 		return "Github Repo"
+	case MenuItemWriting:
+		return "Writing"
+	case MenuItemSearch:
+		return "Search"
+	case MenuItemAdmin:
+		return "Admin"
 	default:
 		return "None"
 	}
 }
 
+// Key is the stable name menu overrides are stored under.
+func (m MenuItem) Key() string {
+	switch m {
+	case MenuItemAbout:
+		return "about"
+	case MenuItemContact:
+		return "contact"
+	case MenuItemGithub:
+		return "github"
+	case MenuItemWriting:
+		return "writing"
+	case MenuItemSearch:
+		return "search"
+	case MenuItemAdmin:
+		return "admin"
+	default:
+		return ""
+	}
+}
+
 func MenuItemFromString(s string) MenuItem {
 	switch s {
 	case "About":
@@ -45,11 +78,86 @@ func MenuItemFromString(s string) MenuItem {
 		return MenuItemContact
 	case "Github Repo":
 		return MenuItemGithub
+	case "Writing":
+		return MenuItemWriting
+	case "Search":
+		return MenuItemSearch
+	case "Admin":
+		return MenuItemAdmin
 	default:
 		return MenuItemNone
 	}
 }
 
+// MenuEntry is one public entry of the main menu as visitors see it.
+type MenuEntry struct {
+	Item        MenuItem
+	Title       string
+	Description string
+	Hidden      bool
+}
+
+// SessionCounter counts the visitors connected right now.
+type SessionCounter interface {
+	Count() int
+}
+
+// onlineRefreshInterval is how often the menu recounts visitors.
+const onlineRefreshInterval = 5 * time.Second
+
+// MenuStore holds the admin's changes to the main menu.
+type MenuStore interface {
+	ListMenuItems(ctx context.Context) ([]db.MenuItem, error)
+}
+
+// DefaultMenu is the built-in public menu. The Github entry is only listed
+// when a projects source is configured.
+func DefaultMenu(projects bool) []MenuEntry {
+	entries := []MenuEntry{
+		{Item: MenuItemAbout, Title: "About", Description: "Find out more about my skills and experience"},
+		{Item: MenuItemWriting, Title: "Writing", Description: "Notes and articles"},
+		{Item: MenuItemSearch, Title: "Search", Description: "Search the resume and writing"},
+		{Item: MenuItemContact, Title: "Contact Me", Description: "Send me an email!!!"},
+	}
+	if projects {
+		entries = append(entries, MenuEntry{Item: MenuItemGithub, Title: "Github Repo", Description: "Explore my side projects"})
+	}
+	return entries
+}
+
+// ApplyMenuOverrides returns entries with the stored titles, order and
+// visibility applied. Entries without an override keep their place after
+// the ones that have one.
+func ApplyMenuOverrides(entries []MenuEntry, overrides []db.MenuItem) []MenuEntry {
+	byKey := make(map[string]db.MenuItem, len(overrides))
+	for _, o := range overrides {
+		byKey[o.Key] = o
+	}
+	out := make([]MenuEntry, len(entries))
+	copy(out, entries)
+	position := func(e MenuEntry) int {
+		if o, ok := byKey[e.Item.Key()]; ok {
+			return o.Position
+		}
+		return len(overrides)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return position(out[i]) < position(out[j]) })
+	for i, e := range out {
+		o, ok := byKey[e.Item.Key()]
+		if !ok {
+			continue
+		}
+		if o.Title != "" {
+			out[i].Title = o.Title
+		}
+		if o.Description != "" {
+			out[i].Description = o.Description
+		}
+		out[i].Hidden = o.Hidden
+	}
+	return out
+}
+
 type MenuModel struct {
 	Text             string
 	Quitting         bool
@@ -62,27 +170,111 @@ type MenuModel struct {
 	KeyMap           MenuKeyMap
 	SelectedMenuItem MenuItem
 	MenuList         list.Model
+	Visitor          Visitor
+	Projects         bool
+	Store            MenuStore
+	Online           SessionCounter
+	themed
+	// onlineTick tells the recount ticks of this visit to the menu apart
+	// from those of earlier ones.
+	onlineTick int
 }
 
 type item struct {
+	kind        MenuItem
 	title, desc string
 }
 
+type menuLoadedMsg struct {
+	overrides []db.MenuItem
+	err       error
+}
+
+// MenuChangedMsg tells open menus that an admin edited the menu.
+type MenuChangedMsg struct{}
+
+type onlineTickMsg struct {
+	tick int
+}
+
 func (i item) Title() string       { return i.title }
 func (i item) Description() string { return i.desc }
 func (i item) FilterValue() string { return i.title }
 
 func (m *MenuModel) Init() tea.Cmd {
-	return tea.SetWindowTitle("Dragon's Lair")
+	m.onlineTick++
+	return tea.Batch(tea.SetWindowTitle("Dragon's Lair"), m.load(), m.tickOnline())
+}
+
+// tickOnline schedules the next redraw of the visitor count.
+func (m *MenuModel) tickOnline() tea.Cmd {
+	if m.Online == nil {
+		return nil
+	}
+	tick := m.onlineTick
+	return tea.Tick(onlineRefreshInterval, func(time.Time) tea.Msg { return onlineTickMsg{tick: tick} })
+}
+
+func (m *MenuModel) load() tea.Cmd {
+	store := m.Store
+	if store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		overrides, err := store.ListMenuItems(ctx)
+		return menuLoadedMsg{overrides: overrides, err: err}
+	}
+}
+
+// menuItems lists the visible entries, followed by the admin's own entry.
+func menuItems(visitor Visitor, projects bool, overrides []db.MenuItem) []list.Item {
+	var items []list.Item
+	for _, e := range ApplyMenuOverrides(DefaultMenu(projects), overrides) {
+		if !e.Hidden {
+			items = append(items, item{kind: e.Item, title: e.Title, desc: e.Description})
+		}
+	}
+	if visitor.Admin {
+		items = append(items, item{kind: MenuItemAdmin, title: "Admin", desc: "Sessions, messages, menu and notices"})
+	}
+	return items
+}
+
+// CapturesKey keeps every key but ctrl+f, and that too while the visitor
+// types a filter: esc leaves the app from the menu rather than going back.
+func (m *MenuModel) CapturesKey(msg tea.KeyMsg) bool {
+	return m.MenuList.FilterState() == list.Filtering || !isForwardKey(msg)
 }
 
 func (m *MenuModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.updateDimensions(msg.Width, msg.Height)
+	case menuLoadedMsg:
+		if msg.err != nil {
+			m.Visitor.Logger().Error("Could not load menu items", "err", msg.err)
+			return m, nil
+		}
+		return m, m.MenuList.SetItems(menuItems(m.Visitor, m.Projects, msg.overrides))
+	case MenuChangedMsg:
+		return m, m.load()
+	case onlineTickMsg:
+		if msg.tick != m.onlineTick {
+			return m, nil
+		}
+		return m, m.tickOnline()
 	case tea.KeyMsg:
+		if m.MenuList.FilterState() == list.Filtering {
+			break
+		}
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
+			if m.MenuList.FilterState() == list.FilterApplied {
+				m.MenuList.ResetFilter()
+				return m, nil
+			}
 			m.Quitting = true
 			return m, tea.Quit
 		case "ctrl+z":
@@ -99,22 +291,21 @@ func (m *MenuModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 		case "enter":
 			i, ok := m.MenuList.SelectedItem().(item)
 			if ok {
-				m.SelectedMenuItem = MenuItemFromString(i.title)
+				m.SelectedMenuItem = i.kind
 			}
 			switch m.SelectedMenuItem {
 			case MenuItemAbout:
-				var cmd tea.Cmd
-				s := GetAboutModel(m.Width, m.Height)
-				s.Init()
-				return s, tea.Batch(cmd, tea.SetWindowTitle("About Me"))
+				return m, Navigate(RouteAbout, nil)
 			case MenuItemContact:
-				var cmd tea.Cmd
-				s := GetContactModel(m.Width, m.Height)
-				s.Init()
-				return s, tea.Batch(cmd, tea.SetWindowTitle("Contact Me"))
+				return m, Navigate(RouteContact, nil)
+			case MenuItemWriting:
+				return m, Navigate(RouteDocs, nil)
+			case MenuItemSearch:
+				return m, Navigate(RouteSearch, nil)
+			case MenuItemAdmin:
+				return m, Navigate(RouteAdmin, nil)
 			case MenuItemGithub:
-				// TODO: Handle Github repo navigation
-				return m, tea.Quit
+				return m, Navigate(RouteProjects, nil)
 			default:
 				return m, tea.Quit
 			}
@@ -126,9 +317,15 @@ func (m *MenuModel) Update(msg tea.Msg) (Page, tea.Cmd) {
 	return m, cmd
 }
 
+// menuChrome is the rows of the menu besides its logo and list: the
+// logo's padding, the key help and the visitor count.
+const menuChrome = 7
+
 func (m *MenuModel) updateDimensions(width, height int) {
 	m.Width = width
 	m.Height = height
+	m.Help.Width = width
+	m.MenuList.SetSize(listSize(width, height, lipgloss.Height(logo(width, height))+menuChrome))
 }
 
 type MenuKeyMap struct{}
@@ -138,6 +335,10 @@ func (k MenuKeyMap) ShortHelp() []key.Binding {
 		key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("↑/k", "move up")),
 		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
 		key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("↓/j", "move down")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "forward")),
+		key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "theme")),
+		key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "plain")),
 		key.NewBinding(key.WithKeys("esc", "q", "ctrl+c"), key.WithHelp("esc", "exit")),
 	}
 }
@@ -146,32 +347,34 @@ func (k MenuKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{k.ShortHelp()}
 }
 
-func NewMenuModel(width, height int) *MenuModel {
-	sp := spinner.New()
-	sp.Spinner = spinner.Globe
-	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#edff83"))
-
-	items := []list.Item{
-		item{title: "About", desc: "Find out more about my skills and experience"},
-		item{title: "Contact Me", desc: "Send me an email!!!"},
-		item{title: "Github Repo", desc: "Explore my side projects"},
-	}
-
+// newListDelegate is the list item styling shared by every list page.
+func newListDelegate(th *theme.Theme) list.DefaultDelegate {
 	d := list.NewDefaultDelegate()
+	th.Restyle(&d.Styles)
 	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
-		Foreground(lipgloss.Color("#fffdf6")).
-		Background(lipgloss.Color("#7f03fc")).
-		Bold(true).
-		Blink(true)
+		Foreground(th.OnAccent).
+		Background(th.Accent).
+		Bold(true)
+	return d
+}
 
-	menuList := list.New(items, d, 81, 15)
+// NewMenuModel builds the main menu from the built-in entries; the admin's
+// changes in store are applied once loaded. The footer counts online's
+// sessions, and is left out if online is nil.
+func NewMenuModel(width, height int, visitor Visitor, projects bool, store MenuStore, online SessionCounter) *MenuModel {
+	sp := spinner.New()
+	sp.Spinner = spinner.Globe
+
+	menuList := list.New(menuItems(visitor, projects, nil), list.NewDefaultDelegate(), 0, 0)
 	menuList.Styles.Title = list.DefaultStyles().Title.Margin(1)
 	menuList.Title = "Learn more about me"
+	if visitor.Returning {
+		menuList.Title = fmt.Sprintf("Welcome back, %s!", visitor.Greeting())
+	}
 	menuList.SetShowStatusBar(false)
-	menuList.SetFilteringEnabled(false)
 	menuList.SetShowHelp(false)
 
-	return &MenuModel{
+	m := &MenuModel{
 		Text:             "Loading App...press q to quit",
 		Quitting:         false,
 		AltScreen:        true,
@@ -183,22 +386,41 @@ func NewMenuModel(width, height int) *MenuModel {
 		KeyMap:           MenuKeyMap{},
 		MenuList:         menuList,
 		SelectedMenuItem: MenuItemNone,
+		Visitor:          visitor,
+		Projects:         projects,
+		Store:            store,
+		Online:           online,
 	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *MenuModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.Spinner.Style = th.Style().Foreground(th.Highlight)
+	m.MenuList.SetDelegate(newListDelegate(th))
+	return nil
 }
 
 func (m MenuModel) View() string {
-	m.Help.Styles.ShortDesc = utils.Style.Faint(true).Blink(true)
+	m.Help.Styles.ShortDesc = m.th.Box().Faint(true)
 	m.Help.ShortSeparator = " • "
-	m.Help.Styles.ShortSeparator = lipgloss.NewStyle().
-		Blink(true).
-		Foreground(lipgloss.Color("#335dcc"))
-	m.Help.Styles.ShortKey = lipgloss.NewStyle().
+	m.Help.Styles.ShortSeparator = m.th.Style().Foreground(m.th.Muted)
+	m.Help.Styles.ShortKey = m.th.Style().
 		Italic(true).
-		Foreground(lipgloss.Color("#fff8db"))
+		Foreground(m.th.Text)
 
-	banner := fmt.Sprintf("\n%s\n", utils.Banner.Render(utils.Rainbow(lipgloss.NewStyle().Blink(true), utils.Logo, utils.Blends)))
+	banner := fmt.Sprintf("\n%s\n", m.th.Style().Padding(1).Render(m.th.Rainbow(m.th.Style(), logo(m.Width, m.Height))))
 	menuList := m.MenuList.View()
 	keymap := fmt.Sprintf("\n%s\n", m.Help.View(m.KeyMap))
+	if m.Online != nil {
+		dragons := "dragons"
+		n := m.Online.Count()
+		if n == 1 {
+			dragons = "dragon"
+		}
+		keymap += m.th.Style().Faint(true).Render(fmt.Sprintf("%d %s in the lair", n, dragons)) + "\n"
+	}
 
 	finalRender := banner + menuList + keymap
 
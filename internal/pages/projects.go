@@ -0,0 +1,310 @@
+package pages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/projects"
+	"DragonTUI/internal/theme"
+	"DragonTUI/internal/utils"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProjectsModel lists repositories from a projects.Source and previews their
+// READMEs.
+type ProjectsModel struct {
+	Width    int
+	Height   int
+	Help     help.Model
+	KeyMap   ProjectsKeyMap
+	List     list.Model
+	Viewport viewport.Model
+	Source   projects.Source
+	Reading  *projects.Project
+	Loading  bool
+	Err      error
+	themed
+}
+
+type projectItem struct {
+	project projects.Project
+}
+
+func (i projectItem) Title() string { return i.project.Name }
+
+func (i projectItem) Description() string {
+	var parts []string
+	if i.project.Language != "" {
+		parts = append(parts, i.project.Language)
+	}
+	parts = append(parts, fmt.Sprintf("★ %d", i.project.Stars))
+	if !i.project.UpdatedAt.IsZero() {
+		parts = append(parts, "updated "+i.project.UpdatedAt.Local().Format("2006-01-02"))
+	}
+	if i.project.Description != "" {
+		parts = append(parts, i.project.Description)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (i projectItem) FilterValue() string {
+	return i.project.Name + " " + i.project.Language + " " + i.project.Description
+}
+
+type projectsLoadedMsg struct {
+	projects []projects.Project
+	err      error
+}
+
+type readmeLoadedMsg struct {
+	name     string
+	rendered string
+	err      error
+}
+
+func NewProjectsModel(width, height int, source projects.Source) *ProjectsModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	l.Title = "Projects"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+
+	m := &ProjectsModel{
+		Width:    width,
+		Height:   height,
+		Help:     help.New(),
+		KeyMap:   ProjectsKeyMap{},
+		List:     l,
+		Viewport: viewport.New(width, height),
+		Source:   source,
+		Loading:  true,
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *ProjectsModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.List.SetDelegate(newListDelegate(th))
+	if m.Reading != nil && !m.Loading {
+		return m.loadReadme(m.Reading.Name)
+	}
+	return nil
+}
+
+func (m *ProjectsModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Projects"), m.load())
+}
+
+func (m *ProjectsModel) load() tea.Cmd {
+	source := m.Source
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		found, err := source.Projects(ctx)
+		return projectsLoadedMsg{projects: found, err: err}
+	}
+}
+
+func (m *ProjectsModel) loadReadme(name string) tea.Cmd {
+	source, style, profile, width := m.Source, m.th.Markdown(), m.th.Profile(), m.Width
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		md, err := source.README(ctx, name)
+		if errors.Is(err, projects.ErrNoReadme) {
+			return readmeLoadedMsg{name: name, rendered: "\n  This project has no README.\n"}
+		}
+		if err != nil {
+			return readmeLoadedMsg{name: name, err: err}
+		}
+		rendered, err := content.RenderMarkdown(md, style, profile, width)
+		return readmeLoadedMsg{name: name, rendered: rendered, err: err}
+	}
+}
+
+// CapturesKey keeps esc for closing the README being read or clearing the
+// filter, and every key while the visitor types a filter.
+func (m *ProjectsModel) CapturesKey(msg tea.KeyMsg) bool {
+	switch {
+	case m.List.FilterState() == list.Filtering:
+		return true
+	case m.Reading != nil, m.List.FilterState() == list.FilterApplied:
+		return isBackKey(msg)
+	}
+	return false
+}
+
+func (m *ProjectsModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		rewrap := msg.Width != m.Width
+		m.updateDimensions(msg.Width, msg.Height)
+		if rewrap && m.Reading != nil && !m.Loading {
+			return m, m.loadReadme(m.Reading.Name)
+		}
+
+	case projectsLoadedMsg:
+		m.Loading = false
+		m.Err = msg.err
+		items := make([]list.Item, len(msg.projects))
+		for i, p := range msg.projects {
+			items[i] = projectItem{project: p}
+		}
+		return m, m.List.SetItems(items)
+
+	case readmeLoadedMsg:
+		if m.Reading == nil || m.Reading.Name != msg.name {
+			return m, nil
+		}
+		// A README restyled by a theme change keeps its scroll position.
+		restyled := !m.Loading
+		m.Loading = false
+		m.Err = msg.err
+		m.Viewport.SetContent(msg.rendered)
+		if !restyled {
+			m.Viewport.GotoTop()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.List.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "esc", "backspace":
+			if m.Reading != nil {
+				m.Reading = nil
+				m.Err = nil
+				return m, nil
+			}
+			m.List.ResetFilter()
+			return m, nil
+		case "r":
+			if m.Reading != nil {
+				return m, nil
+			}
+			m.Loading = true
+			return m, m.load()
+		case "b":
+			if _, ok := m.Source.(RepoBrowser); !ok || m.Reading != nil {
+				return m, nil
+			}
+			if i, ok := m.List.SelectedItem().(projectItem); ok {
+				return m, Navigate(RouteRepo, map[string]string{"repo": i.project.Name})
+			}
+			return m, nil
+		case "enter":
+			if m.Reading != nil {
+				return m, nil
+			}
+			i, ok := m.List.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			project := i.project
+			m.Reading = &project
+			m.Loading = true
+			m.Err = nil
+			m.Viewport.SetContent("")
+			return m, m.loadReadme(project.Name)
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.Reading != nil {
+		m.Viewport, cmd = m.Viewport.Update(msg)
+	} else {
+		m.List, cmd = m.List.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *ProjectsModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.List.SetSize(listSize(width, height, 2))
+}
+
+func (m *ProjectsModel) headerView() string {
+	p := m.Reading
+	title := titleStyle(m.th).Render(m.th.Rainbow(m.th.Style().Bold(true), p.Name))
+	line := m.th.Rule(utils.Max(0, m.Viewport.Width-lipgloss.Width(title)))
+	header := lipgloss.JoinHorizontal(lipgloss.Center, title, m.th.Style().Foreground(m.th.Error).Render(line))
+	if p.URL != "" {
+		header += "\n" + m.th.Faint().Render(p.URL)
+	}
+	return header
+}
+
+func (m *ProjectsModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+	m.KeyMap.browse = false
+	if _, ok := m.Source.(RepoBrowser); ok {
+		m.KeyMap.browse = true
+	}
+	helpView := m.Help.View(m.KeyMap)
+
+	var body string
+	switch {
+	case m.Reading != nil:
+		header := m.headerView()
+		m.Viewport.Width = m.Width
+		m.Viewport.Height = utils.Max(0, m.Height-lipgloss.Height(header)-lipgloss.Height(helpView)-1)
+		body = header + "\n" + m.Viewport.View()
+		if m.Loading {
+			body = header + "\n\n  Loading README..."
+		}
+	case m.Loading && len(m.List.Items()) == 0:
+		body = "\n  Loading projects...\n"
+	case len(m.List.Items()) == 0 && m.Err == nil:
+		body = "\n  No projects to show yet.\n"
+	default:
+		body = m.List.View()
+	}
+	if m.Err != nil {
+		body += "\n" + m.th.Style().Foreground(m.th.Error).Render(m.Err.Error())
+	}
+	s := fmt.Sprintf("%s\n%s", body, helpView)
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type ProjectsKeyMap struct {
+	// browse is set when the source can also browse repositories.
+	browse bool
+}
+
+func (k ProjectsKeyMap) ShortHelp() []key.Binding {
+	bindings := []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "readme")),
+	}
+	if k.browse {
+		bindings = append(bindings, key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "browse")))
+	}
+	return append(bindings,
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	)
+}
+
+func (k ProjectsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,419 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/softserve"
+	"DragonTUI/internal/theme"
+	"DragonTUI/internal/utils"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RepoBrowser is what the repository page needs from a git server.
+type RepoBrowser interface {
+	Branches(ctx context.Context, repo string) ([]string, error)
+	Tree(ctx context.Context, repo, ref, dir string) ([]softserve.TreeEntry, error)
+	Blob(ctx context.Context, repo, ref, file string) (string, error)
+	Commit(ctx context.Context, repo, ref string) (string, error)
+}
+
+type repoView int
+
+const (
+	repoBranches repoView = iota
+	repoTree
+	repoFile
+)
+
+// RepoModel browses one repository, selected by the "repo" navigation
+// parameter: pick a branch, walk its tree, and read files or the latest
+// commit.
+type RepoModel struct {
+	Width    int
+	Height   int
+	Help     help.Model
+	KeyMap   RepoKeyMap
+	Browser  RepoBrowser
+	Repo     string
+	Ref      string
+	Dir      string
+	File     string
+	Mode     repoView
+	Back     repoView // where esc leaves the file view
+	Branches list.Model
+	Tree     list.Model
+	Viewport viewport.Model
+	Loading  bool
+	Err      error
+	themed
+}
+
+// commitTitle is the file view's title while it shows the latest commit.
+const commitTitle = "latest commit"
+
+type branchItem string
+
+func (i branchItem) Title() string       { return string(i) }
+func (i branchItem) Description() string { return "" }
+func (i branchItem) FilterValue() string { return string(i) }
+
+type treeItem struct {
+	entry softserve.TreeEntry
+}
+
+func (i treeItem) Title() string {
+	if i.entry.Dir {
+		return i.entry.Name + "/"
+	}
+	return i.entry.Name
+}
+func (i treeItem) Description() string { return i.entry.Mode + "  " + i.entry.Size }
+func (i treeItem) FilterValue() string { return i.entry.Name }
+
+type repoBranchesMsg struct {
+	repo     string
+	branches []string
+	err      error
+}
+
+type repoTreeMsg struct {
+	dir     string
+	entries []softserve.TreeEntry
+	err     error
+}
+
+type repoFileMsg struct {
+	title    string
+	rendered string
+	err      error
+}
+
+// newRepoDelegate shows entries' descriptions, or packs them one to a line
+// when they have none.
+func newRepoDelegate(d list.DefaultDelegate, description bool) list.DefaultDelegate {
+	d.ShowDescription = description
+	if !description {
+		d.SetSpacing(0)
+	}
+	return d
+}
+
+func NewRepoModel(width, height int, browser RepoBrowser) *RepoModel {
+	newList := func(title string, description bool) list.Model {
+		l := list.New(nil, newRepoDelegate(list.NewDefaultDelegate(), description), 0, 0)
+		l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+		l.Title = title
+		l.SetShowStatusBar(false)
+		l.SetShowHelp(false)
+		return l
+	}
+	m := &RepoModel{
+		Width:    width,
+		Height:   height,
+		Help:     help.New(),
+		KeyMap:   RepoKeyMap{},
+		Browser:  browser,
+		Branches: newList("Branches", false),
+		Tree:     newList("Files", true),
+		Viewport: viewport.New(width, height),
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+// SetTheme restyles the lists and highlights the open file again in th's
+// code style.
+func (m *RepoModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.Branches.SetDelegate(newRepoDelegate(newListDelegate(th), false))
+	m.Tree.SetDelegate(newRepoDelegate(newListDelegate(th), true))
+	if m.Mode == repoFile && m.File != commitTitle {
+		return m.loadFile(m.File)
+	}
+	return nil
+}
+
+func (m *RepoModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *RepoModel) Enter(params map[string]string) tea.Cmd {
+	repo := params["repo"]
+	title := tea.SetWindowTitle(repo)
+	if repo == m.Repo {
+		return title
+	}
+	m.Repo, m.Ref, m.Dir, m.File = repo, "", "", ""
+	m.Mode = repoBranches
+	m.Branches.SetItems(nil)
+	m.Branches.Title = repo + " · branches"
+	m.Err = nil
+	m.Loading = true
+	return tea.Batch(title, m.loadBranches())
+}
+
+func (m *RepoModel) run(fn func(ctx context.Context) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return fn(ctx)
+	}
+}
+
+func (m *RepoModel) loadBranches() tea.Cmd {
+	browser, repo := m.Browser, m.Repo
+	return m.run(func(ctx context.Context) tea.Msg {
+		branches, err := browser.Branches(ctx, repo)
+		return repoBranchesMsg{repo: repo, branches: branches, err: err}
+	})
+}
+
+func (m *RepoModel) loadTree(dir string) tea.Cmd {
+	browser, repo, ref := m.Browser, m.Repo, m.Ref
+	m.Loading = true
+	return m.run(func(ctx context.Context) tea.Msg {
+		entries, err := browser.Tree(ctx, repo, ref, dir)
+		return repoTreeMsg{dir: dir, entries: entries, err: err}
+	})
+}
+
+func (m *RepoModel) loadFile(file string) tea.Cmd {
+	browser, repo, ref, style, profile := m.Browser, m.Repo, m.Ref, m.th.Code, m.th.Profile()
+	m.Loading = true
+	return m.run(func(ctx context.Context) tea.Msg {
+		source, err := browser.Blob(ctx, repo, ref, file)
+		if err != nil {
+			return repoFileMsg{title: file, err: err}
+		}
+		rendered, err := content.RenderCode(file, source, style, profile)
+		return repoFileMsg{title: file, rendered: rendered, err: err}
+	})
+}
+
+func (m *RepoModel) loadCommit() tea.Cmd {
+	browser, repo, ref := m.Browser, m.Repo, m.Ref
+	m.Loading = true
+	return m.run(func(ctx context.Context) tea.Msg {
+		commit, err := browser.Commit(ctx, repo, ref)
+		return repoFileMsg{title: commitTitle, rendered: commit, err: err}
+	})
+}
+
+// CapturesKey keeps every key while the visitor types a filter, and esc
+// for going up from a file, a filter or a directory to the branches.
+func (m *RepoModel) CapturesKey(msg tea.KeyMsg) bool {
+	if m.current().FilterState() == list.Filtering {
+		return true
+	}
+	return isBackKey(msg) && (m.Mode == repoFile || m.Mode == repoTree || m.current().FilterState() == list.FilterApplied)
+}
+
+func (m *RepoModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+
+	case repoBranchesMsg:
+		if msg.repo != m.Repo {
+			return m, nil
+		}
+		m.Loading = false
+		m.Err = msg.err
+		items := make([]list.Item, len(msg.branches))
+		for i, b := range msg.branches {
+			items[i] = branchItem(b)
+		}
+		return m, m.Branches.SetItems(items)
+
+	case repoTreeMsg:
+		m.Loading = false
+		m.Err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		m.Dir = msg.dir
+		m.Mode = repoTree
+		m.Tree.Title = m.breadcrumb()
+		m.Tree.ResetFilter()
+		items := make([]list.Item, len(msg.entries))
+		for i, e := range msg.entries {
+			items[i] = treeItem{entry: e}
+		}
+		return m, m.Tree.SetItems(items)
+
+	case repoFileMsg:
+		m.Loading = false
+		m.Err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		// Restyling the open file keeps its scroll position.
+		reopened := m.Mode == repoFile && msg.title == m.File
+		m.File = msg.title
+		if m.Mode != repoFile {
+			m.Back = m.Mode
+		}
+		m.Mode = repoFile
+		m.Viewport.SetContent(msg.rendered)
+		if !reopened {
+			m.Viewport.GotoTop()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.current().FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "esc", "backspace":
+			m.Err = nil
+			switch {
+			case m.Mode == repoFile:
+				m.Mode = m.Back
+				return m, nil
+			case m.current().FilterState() == list.FilterApplied:
+				m.current().ResetFilter()
+				return m, nil
+			case m.Mode == repoTree && m.Dir != "":
+				return m, m.loadTree(parentDir(m.Dir))
+			case m.Mode == repoTree:
+				m.Mode = repoBranches
+			}
+			return m, nil
+		case "c":
+			if m.Mode == repoBranches {
+				if b, ok := m.Branches.SelectedItem().(branchItem); ok {
+					m.Ref = string(b)
+				}
+			}
+			if m.Ref == "" || m.Mode == repoFile {
+				return m, nil
+			}
+			return m, m.loadCommit()
+		case "enter":
+			switch m.Mode {
+			case repoBranches:
+				if b, ok := m.Branches.SelectedItem().(branchItem); ok {
+					m.Ref = string(b)
+					return m, m.loadTree("")
+				}
+			case repoTree:
+				if i, ok := m.Tree.SelectedItem().(treeItem); ok {
+					p := path.Join(m.Dir, i.entry.Name)
+					if i.entry.Dir {
+						return m, m.loadTree(p)
+					}
+					return m, m.loadFile(p)
+				}
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.Mode {
+	case repoBranches:
+		m.Branches, cmd = m.Branches.Update(msg)
+	case repoTree:
+		m.Tree, cmd = m.Tree.Update(msg)
+	case repoFile:
+		m.Viewport, cmd = m.Viewport.Update(msg)
+	}
+	return m, cmd
+}
+
+// current is the list on screen; the file view has none, so it reports the
+// tree, which is what esc returns to.
+func (m *RepoModel) current() *list.Model {
+	if m.Mode == repoBranches {
+		return &m.Branches
+	}
+	return &m.Tree
+}
+
+func parentDir(dir string) string {
+	parent := path.Dir(dir)
+	if parent == "." || parent == "/" {
+		return ""
+	}
+	return parent
+}
+
+func (m *RepoModel) breadcrumb() string {
+	s := m.Repo + " @ " + m.Ref
+	if m.Dir != "" {
+		s += " / " + m.Dir
+	}
+	return s
+}
+
+func (m *RepoModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.Branches.SetSize(listSize(width, height, 3))
+	m.Tree.SetSize(listSize(width, height, 3))
+}
+
+func (m *RepoModel) headerView() string {
+	title := titleStyle(m.th).Render(m.th.Rainbow(m.th.Style().Bold(true), m.Repo+" @ "+m.Ref+" / "+m.File))
+	line := m.th.Rule(utils.Max(0, m.Viewport.Width-lipgloss.Width(title)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, m.th.Style().Foreground(m.th.Error).Render(line))
+}
+
+func (m *RepoModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+	helpView := m.Help.View(m.KeyMap)
+
+	var body string
+	switch m.Mode {
+	case repoBranches:
+		body = m.Branches.View()
+	case repoTree:
+		body = m.Tree.View()
+	case repoFile:
+		header := m.headerView()
+		m.Viewport.Width = m.Width
+		m.Viewport.Height = utils.Max(0, m.Height-lipgloss.Height(header)-lipgloss.Height(helpView)-1)
+		body = header + "\n" + m.Viewport.View()
+	}
+	if m.Loading {
+		body += "\n  Loading..."
+	}
+	if m.Err != nil {
+		body += "\n" + m.th.Style().Foreground(m.th.Error).Render(m.Err.Error())
+	}
+	s := fmt.Sprintf("%s\n%s", body, helpView)
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type RepoKeyMap struct{}
+
+func (k RepoKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "latest commit")),
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "up")),
+	}
+}
+
+func (k RepoKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,154 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"DragonTUI/internal/db"
+)
+
+// ResumeStore is the part of the database the About page reads from.
+type ResumeStore interface {
+	LoadResume(ctx context.Context) (*db.Resume, error)
+}
+
+// LoadResumeMarkdown builds the About page markdown from the database,
+// falling back to the markdown file at fallbackPath when no store is
+// configured or it holds no resume yet.
+func LoadResumeMarkdown(store ResumeStore, fallbackPath string) (string, error) {
+	if store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		resume, err := store.LoadResume(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !resume.IsEmpty() {
+			return resumeMarkdown(resume), nil
+		}
+	}
+
+	fileContent, err := os.ReadFile(fallbackPath)
+	if err != nil {
+		return "", fmt.Errorf("could not load %s: %w", fallbackPath, err)
+	}
+	return string(fileContent), nil
+}
+
+func resumeMarkdown(r *db.Resume) string {
+	var b strings.Builder
+
+	if p := r.Profile; p != nil {
+		fmt.Fprintf(&b, "# %s\n\n", p.Name)
+		if p.Headline != "" {
+			fmt.Fprintf(&b, "**%s**\n\n", p.Headline)
+		}
+		var contact []string
+		for _, s := range []string{p.Location, p.Email} {
+			if s != "" {
+				contact = append(contact, s)
+			}
+		}
+		if len(contact) > 0 {
+			fmt.Fprintf(&b, "%s\n\n", strings.Join(contact, " · "))
+		}
+		if p.Summary != "" {
+			fmt.Fprintf(&b, "%s\n\n", p.Summary)
+		}
+	}
+
+	if len(r.Links) > 0 {
+		b.WriteString("## Links\n\n")
+		for _, l := range r.Links {
+			fmt.Fprintf(&b, "- [%s](%s)\n", l.Label, l.URL)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Skills) > 0 {
+		b.WriteString("## Skills\n\n")
+		var (
+			categories []string
+			byCategory = make(map[string][]string)
+		)
+		for _, s := range r.Skills {
+			if _, ok := byCategory[s.Category]; !ok {
+				categories = append(categories, s.Category)
+			}
+			byCategory[s.Category] = append(byCategory[s.Category], s.Name)
+		}
+		for _, c := range categories {
+			if c == "" {
+				fmt.Fprintf(&b, "- %s\n", strings.Join(byCategory[c], ", "))
+				continue
+			}
+			fmt.Fprintf(&b, "- **%s:** %s\n", c, strings.Join(byCategory[c], ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Experience) > 0 {
+		b.WriteString("## Experience\n\n")
+		for _, e := range r.Experience {
+			fmt.Fprintf(&b, "### %s — %s\n\n", e.Role, e.Company)
+			if period := dateRange(e.StartDate, e.EndDate); period != "" || e.Location != "" {
+				fmt.Fprintf(&b, "_%s_\n\n", strings.Trim(period+" · "+e.Location, " ·"))
+			}
+			if e.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", e.Description)
+			}
+		}
+	}
+
+	if len(r.Projects) > 0 {
+		b.WriteString("## Projects\n\n")
+		for _, p := range r.Projects {
+			if p.URL != "" {
+				fmt.Fprintf(&b, "### [%s](%s)\n\n", p.Name, p.URL)
+			} else {
+				fmt.Fprintf(&b, "### %s\n\n", p.Name)
+			}
+			if p.Tech != "" {
+				fmt.Fprintf(&b, "_%s_\n\n", p.Tech)
+			}
+			if p.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", p.Description)
+			}
+		}
+	}
+
+	if len(r.Education) > 0 {
+		b.WriteString("## Education\n\n")
+		for _, e := range r.Education {
+			fmt.Fprintf(&b, "### %s\n\n", e.Institution)
+			if e.Degree != "" {
+				fmt.Fprintf(&b, "%s\n\n", e.Degree)
+			}
+			if period := dateRange(e.StartDate, e.EndDate); period != "" {
+				fmt.Fprintf(&b, "_%s_\n\n", period)
+			}
+			if e.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", e.Description)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func dateRange(start, end string) string {
+	switch {
+	case start == "" && end == "":
+		return ""
+	case end == "":
+		return start + " – Present"
+	case start == "":
+		return end
+	default:
+		return start + " – " + end
+	}
+}
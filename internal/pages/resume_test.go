@@ -0,0 +1,113 @@
+package pages
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"DragonTUI/internal/db"
+)
+
+func TestResumeMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		resume db.Resume
+		want   string
+	}{
+		{
+			name: "profile",
+			resume: db.Resume{Profile: &db.Profile{
+				Name: "Dragon", Headline: "Keeper", Email: "dragon@example.com", Location: "Lair", Summary: "Hoards gold.",
+			}},
+			want: "# Dragon\n\n**Keeper**\n\nLair · dragon@example.com\n\nHoards gold.\n\n",
+		},
+		{
+			name:   "bare profile",
+			resume: db.Resume{Profile: &db.Profile{Name: "Dragon"}},
+			want:   "# Dragon\n\n",
+		},
+		{
+			name:   "links",
+			resume: db.Resume{Links: []db.Link{{Label: "Site", URL: "https://example.com"}}},
+			want:   "## Links\n\n- [Site](https://example.com)\n\n",
+		},
+		{
+			name: "skills by category in order",
+			resume: db.Resume{Skills: []db.Skill{
+				{Category: "Languages", Name: "Go"}, {Name: "Fire"}, {Category: "Languages", Name: "C"},
+			}},
+			want: "## Skills\n\n- **Languages:** Go, C\n- Fire\n\n",
+		},
+		{
+			name: "experience",
+			resume: db.Resume{Experience: []db.Experience{
+				{Company: "Lair", Role: "Keeper", Location: "Mountain", StartDate: "2020", Description: "Guards gold."},
+				{Company: "Cave", Role: "Intern", StartDate: "2018", EndDate: "2019"},
+				{Company: "Home", Role: "Hatchling"},
+			}},
+			want: "## Experience\n\n" +
+				"### Keeper — Lair\n\n_2020 – Present · Mountain_\n\nGuards gold.\n\n" +
+				"### Intern — Cave\n\n_2018 – 2019_\n\n" +
+				"### Hatchling — Home\n\n",
+		},
+		{
+			name: "projects",
+			resume: db.Resume{Projects: []db.Project{
+				{Name: "DragonTUI", URL: "https://example.com", Tech: "Go", Description: "A resume over SSH."},
+				{Name: "Hoard"},
+			}},
+			want: "## Projects\n\n### [DragonTUI](https://example.com)\n\n_Go_\n\nA resume over SSH.\n\n### Hoard\n\n",
+		},
+		{
+			name:   "education",
+			resume: db.Resume{Education: []db.Education{{Institution: "School", Degree: "Flight", EndDate: "2014"}}},
+			want:   "## Education\n\n### School\n\nFlight\n\n_2014_\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resumeMarkdown(&tt.resume); got != tt.want {
+				t.Errorf("resumeMarkdown() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeResumeStore struct {
+	resume *db.Resume
+	err    error
+}
+
+func (s fakeResumeStore) LoadResume(context.Context) (*db.Resume, error) {
+	return s.resume, s.err
+}
+
+func TestLoadResumeMarkdown(t *testing.T) {
+	fallback := filepath.Join(t.TempDir(), "resume.md")
+	if err := os.WriteFile(fallback, []byte("# From the file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	errLoad := errors.New("database is locked")
+
+	tests := []struct {
+		name    string
+		store   ResumeStore
+		want    string
+		wantErr error
+	}{
+		{"no store", nil, "# From the file\n", nil},
+		{"empty resume", fakeResumeStore{resume: &db.Resume{}}, "# From the file\n", nil},
+		{"resume", fakeResumeStore{resume: &db.Resume{Profile: &db.Profile{Name: "Dragon"}}}, "# Dragon\n\n", nil},
+		{"error", fakeResumeStore{err: errLoad}, "", errLoad},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadResumeMarkdown(tt.store, fallback)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("LoadResumeMarkdown() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
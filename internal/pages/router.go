@@ -0,0 +1,245 @@
+package pages
+
+import (
+	"fmt"
+
+	"DragonTUI/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+type Route string
+
+const (
+	RouteMenu     Route = "menu"
+	RouteAbout    Route = "about"
+	RouteContact  Route = "contact"
+	RouteInbox    Route = "inbox"
+	RouteDocs     Route = "docs"
+	RouteDoc      Route = "doc"
+	RouteSearch   Route = "search"
+	RouteProjects Route = "projects"
+	RouteRepo     Route = "repo"
+
+	RouteAdmin          Route = "admin"
+	RouteAdminSessions  Route = "admin/sessions"
+	RouteAdminMenu      Route = "admin/menu"
+	RouteAdminBroadcast Route = "admin/broadcast"
+)
+
+// NavigateMsg asks the router to make Route the current page. Params are
+// handed to the page if it implements Enterer.
+type NavigateMsg struct {
+	Route  Route
+	Params map[string]string
+}
+
+// BackMsg pops the history stack and returns to the previous page.
+type BackMsg struct{}
+
+// ForwardMsg re-enters the page that was last left with BackMsg.
+type ForwardMsg struct{}
+
+func Navigate(route Route, params map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		return NavigateMsg{Route: route, Params: params}
+	}
+}
+
+func Back() tea.Msg {
+	return BackMsg{}
+}
+
+func Forward() tea.Msg {
+	return ForwardMsg{}
+}
+
+// KeyCapturer is implemented by pages that sometimes need esc, backspace or
+// ctrl+f themselves, such as to close a search bar, clear a list filter or
+// edit text. The router only goes back or forward on keys the page doesn't
+// capture.
+type KeyCapturer interface {
+	CapturesKey(msg tea.KeyMsg) bool
+}
+
+// Enterer is implemented by pages that need the parameters they were
+// navigated to with.
+type Enterer interface {
+	Enter(params map[string]string) tea.Cmd
+}
+
+type PageFactory func(width, height int) Page
+
+type location struct {
+	route  Route
+	params map[string]string
+}
+
+// Router owns the pages of a single SSH session. Pages are created lazily from
+// their registered factory and cached, so state such as form input or scroll
+// position survives navigating away and back, but is never shared between
+// visitors.
+type Router struct {
+	factories map[Route]PageFactory
+	pages     map[Route]Page
+
+	current location
+	back    []location
+	forward []location
+
+	width  int
+	height int
+	theme  *theme.Theme
+}
+
+func NewRouter(width, height int, th *theme.Theme) *Router {
+	return &Router{
+		factories: make(map[Route]PageFactory),
+		pages:     make(map[Route]Page),
+		width:     width,
+		height:    height,
+		theme:     th,
+	}
+}
+
+func (r *Router) Register(route Route, factory PageFactory) {
+	r.factories[route] = factory
+}
+
+// Start makes route the initial page without recording any history.
+func (r *Router) Start(route Route) (tea.Cmd, error) {
+	if _, err := r.page(route); err != nil {
+		return nil, err
+	}
+	r.current = location{route: route}
+	return r.enter(r.current), nil
+}
+
+func (r *Router) Current() Page {
+	return r.pages[r.current.route]
+}
+
+func (r *Router) CurrentRoute() Route {
+	return r.current.route
+}
+
+func (r *Router) Theme() *theme.Theme {
+	return r.theme
+}
+
+// SetTheme redraws every page created so far in th, and creates later ones
+// in it.
+func (r *Router) SetTheme(th *theme.Theme) tea.Cmd {
+	r.theme = th
+	var cmds []tea.Cmd
+	for _, p := range r.pages {
+		cmds = append(cmds, applyTheme(p, th))
+	}
+	return tea.Batch(cmds...)
+}
+
+// applyTheme binds the styles of the page's bubbles components to th's
+// renderer, and tells Themed pages about th.
+func applyTheme(p Page, th *theme.Theme) tea.Cmd {
+	th.Restyle(p)
+	if t, ok := p.(Themed); ok {
+		return t.SetTheme(th)
+	}
+	return nil
+}
+
+func (r *Router) CanGoBack() bool {
+	return len(r.back) > 0
+}
+
+func (r *Router) CanGoForward() bool {
+	return len(r.forward) > 0
+}
+
+func (r *Router) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.width = msg.Width
+		r.height = msg.Height
+
+	case NavigateMsg:
+		if _, err := r.page(msg.Route); err != nil {
+			log.Error("Could not navigate", "route", msg.Route, "err", err)
+			return nil
+		}
+		r.back = append(r.back, r.current)
+		r.forward = nil
+		r.current = location{route: msg.Route, params: msg.Params}
+		return r.enter(r.current)
+
+	case BackMsg:
+		if len(r.back) == 0 {
+			return nil
+		}
+		r.forward = append(r.forward, r.current)
+		r.current = r.back[len(r.back)-1]
+		r.back = r.back[:len(r.back)-1]
+		return r.enter(r.current)
+
+	case ForwardMsg:
+		if len(r.forward) == 0 {
+			return nil
+		}
+		r.back = append(r.back, r.current)
+		r.current = r.forward[len(r.forward)-1]
+		r.forward = r.forward[:len(r.forward)-1]
+		return r.enter(r.current)
+	}
+
+	page := r.Current()
+	if page == nil {
+		return tea.Quit
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if c, ok := page.(KeyCapturer); !ok || !c.CapturesKey(msg) {
+			switch {
+			case isBackKey(msg):
+				return r.Update(BackMsg{})
+			case isForwardKey(msg):
+				return r.Update(ForwardMsg{})
+			}
+		}
+	}
+	page, cmd := page.Update(msg)
+	r.pages[r.current.route] = page
+	return cmd
+}
+
+// isBackKey reports whether msg is a key the router goes back on.
+func isBackKey(msg tea.KeyMsg) bool {
+	return msg.String() == "esc" || msg.String() == "backspace"
+}
+
+// isForwardKey reports whether msg is the key the router goes forward on.
+func isForwardKey(msg tea.KeyMsg) bool {
+	return msg.String() == "ctrl+f"
+}
+
+func (r *Router) page(route Route) (Page, error) {
+	if p, ok := r.pages[route]; ok {
+		return p, nil
+	}
+	factory, ok := r.factories[route]
+	if !ok {
+		return nil, fmt.Errorf("no page registered for route %q", route)
+	}
+	p := factory(r.width, r.height)
+	applyTheme(p, r.theme)
+	r.pages[route] = p
+	return p, nil
+}
+
+func (r *Router) enter(loc location) tea.Cmd {
+	p := r.pages[loc.route]
+	cmds := []tea.Cmd{p.Init()}
+	if e, ok := p.(Enterer); ok {
+		cmds = append(cmds, e.Enter(loc.params))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,131 @@
+package pages
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"DragonTUI/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newContactRouter(t *testing.T) *Router {
+	t.Helper()
+	r := NewRouter(80, 40, theme.Default())
+	r.Register(RouteContact, func(w, h int) Page {
+		return NewContactModel(w, h, Visitor{}, ContactOptions{})
+	})
+	if _, err := r.Start(RouteContact); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestSessionsDoNotShareContactForm(t *testing.T) {
+	names := []string{"AliceAnderson", "BobBrown"}
+	routers := make([]*Router, len(names))
+	for i := range routers {
+		routers[i] = newContactRouter(t)
+	}
+
+	// Each session is driven from its own goroutine, as wish does.
+	var wg sync.WaitGroup
+	for i, r := range routers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Update(tea.WindowSizeMsg{Width: 60 + i*20, Height: 30})
+			for _, c := range names[i] {
+				r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{c}})
+				r.Current().View()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, r := range routers {
+		contact, ok := r.Current().(*ContactModel)
+		if !ok {
+			t.Fatalf("session %d is on %T, want the contact page", i, r.Current())
+		}
+		view := contact.View()
+		if !strings.Contains(view, names[i]) {
+			t.Errorf("session %d lost its name %q:\n%s", i, names[i], view)
+		}
+		if other := names[1-i]; strings.Contains(view, other) {
+			t.Errorf("session %d shows the other session's name %q:\n%s", i, other, view)
+		}
+		if want := 60 + i*20; contact.Width != want {
+			t.Errorf("session %d width is %d, want %d", i, contact.Width, want)
+		}
+	}
+	if routers[0].Current() == routers[1].Current() {
+		t.Fatal("sessions share a contact page")
+	}
+}
+
+// keyPage records the keys the router hands it, and captures those in
+// captures.
+type keyPage struct {
+	captures string
+	got      []string
+}
+
+func (p *keyPage) Init() tea.Cmd { return nil }
+func (p *keyPage) View() string  { return "" }
+
+func (p *keyPage) Update(msg tea.Msg) (Page, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		p.got = append(p.got, msg.String())
+	}
+	return p, nil
+}
+
+func (p *keyPage) CapturesKey(msg tea.KeyMsg) bool {
+	return msg.String() == p.captures
+}
+
+func TestRouterBackAndForwardKeys(t *testing.T) {
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+	backspace := tea.KeyMsg{Type: tea.KeyBackspace}
+	forward := tea.KeyMsg{Type: tea.KeyCtrlF}
+
+	tests := []struct {
+		name     string
+		captures string
+		keys     []tea.KeyMsg
+		want     Route
+		got      []string
+	}{
+		{name: "esc goes back", keys: []tea.KeyMsg{esc}, want: RouteMenu},
+		{name: "backspace goes back", keys: []tea.KeyMsg{backspace}, want: RouteMenu},
+		{name: "ctrl+f goes forward", keys: []tea.KeyMsg{esc, forward}, want: RouteAbout},
+		{name: "captured esc", captures: "esc", keys: []tea.KeyMsg{esc}, want: RouteAbout, got: []string{"esc"}},
+		{name: "other keys reach the page", keys: []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("n")}}, want: RouteAbout, got: []string{"n"}},
+		{name: "unregistered route", keys: nil, want: RouteAbout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			about := &keyPage{captures: tt.captures}
+			r := NewRouter(80, 24, theme.Default())
+			r.Register(RouteMenu, func(w, h int) Page { return &keyPage{captures: "esc"} })
+			r.Register(RouteAbout, func(w, h int) Page { return about })
+			if _, err := r.Start(RouteMenu); err != nil {
+				t.Fatal(err)
+			}
+			r.Update(NavigateMsg{Route: RouteAbout})
+			r.Update(NavigateMsg{Route: "nowhere"})
+
+			for _, k := range tt.keys {
+				r.Update(k)
+			}
+			if got := r.CurrentRoute(); got != tt.want {
+				t.Errorf("on %q, want %q", got, tt.want)
+			}
+			if strings.Join(about.got, " ") != strings.Join(tt.got, " ") {
+				t.Errorf("page got keys %q, want %q", about.got, tt.got)
+			}
+		})
+	}
+}
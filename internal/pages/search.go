@@ -0,0 +1,217 @@
+package pages
+
+import (
+	"fmt"
+	"strconv"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SearchModel searches the resume and every document in the library at
+// once. Selecting a result opens the page with the query highlighted and
+// scrolled to that occurrence.
+type SearchModel struct {
+	Width   int
+	Height  int
+	Help    help.Model
+	KeyMap  SearchKeyMap
+	Input   textinput.Model
+	Results list.Model
+	Index   *content.Index
+	// shown is the query the listed results are for.
+	shown string
+	themed
+}
+
+type searchResult struct {
+	content.Hit
+}
+
+func (r searchResult) Title() string {
+	title := r.Hit.Title
+	if r.Slug == "" {
+		title = "About"
+	}
+	return fmt.Sprintf("%s · line %d", title, r.Match.Line+1)
+}
+func (r searchResult) Description() string { return r.Match.Snippet }
+func (r searchResult) FilterValue() string { return r.Match.Snippet }
+
+// route is the page the result is on; the resume has no slug.
+func (r searchResult) route() Route {
+	if r.Slug == "" {
+		return RouteAbout
+	}
+	return RouteDoc
+}
+
+// searchResultsMsg carries the results for query, which are dropped if the
+// visitor has typed on since.
+type searchResultsMsg struct {
+	query string
+	hits  []content.Hit
+}
+
+func NewSearchModel(width, height int, index *content.Index) *SearchModel {
+	ti := textinput.New()
+	ti.Prompt = "Search: "
+	ti.Placeholder = "type to search the resume and writing"
+	ti.CharLimit = 64
+
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Styles.Title = list.DefaultStyles().Title.Margin(1)
+	l.Title = "Results"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+
+	m := &SearchModel{
+		Width:   width,
+		Height:  height,
+		Help:    help.New(),
+		KeyMap:  SearchKeyMap{},
+		Input:   ti,
+		Results: l,
+		Index:   index,
+	}
+	m.updateDimensions(width, height)
+	return m
+}
+
+func (m *SearchModel) SetTheme(th *theme.Theme) tea.Cmd {
+	m.themed.SetTheme(th)
+	m.Results.SetDelegate(newListDelegate(th))
+	return nil
+}
+
+// Init searches again for the query left in the box, in case the content
+// changed while the visitor was away.
+func (m *SearchModel) Init() tea.Cmd {
+	return tea.Batch(tea.SetWindowTitle("Search"), m.Input.Focus(), m.search())
+}
+
+// search looks the query up in the index off the UI goroutine.
+func (m *SearchModel) search() tea.Cmd {
+	query, index := m.Input.Value(), m.Index
+	if query == "" {
+		return m.showResults(query, nil)
+	}
+	return func() tea.Msg {
+		return searchResultsMsg{query: query, hits: index.Search(query)}
+	}
+}
+
+func (m *SearchModel) showResults(query string, hits []content.Hit) tea.Cmd {
+	items := make([]list.Item, len(hits))
+	for i, h := range hits {
+		items[i] = searchResult{h}
+	}
+	m.shown = query
+	m.Results.Title = "Results"
+	if query != "" {
+		m.Results.Title = fmt.Sprintf("Results (%d)", len(items))
+	}
+	return m.Results.SetItems(items)
+}
+
+// CapturesKey keeps backspace for the search box.
+func (m *SearchModel) CapturesKey(msg tea.KeyMsg) bool {
+	return msg.String() == "backspace"
+}
+
+func (m *SearchModel) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateDimensions(msg.Width, msg.Height)
+		return m, nil
+
+	case ContentUpdatedMsg:
+		if msg.Source == content.Notifier(m.Index) {
+			return m, m.search()
+		}
+		return m, nil
+
+	case searchResultsMsg:
+		if msg.query != m.Input.Value() {
+			return m, nil
+		}
+		return m, m.showResults(msg.query, msg.hits)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "ctrl+z":
+			return m, tea.Suspend
+		case "up", "down", "ctrl+p", "ctrl+n", "pgup", "pgdown":
+			var cmd tea.Cmd
+			m.Results, cmd = m.Results.Update(msg)
+			return m, cmd
+		case "enter":
+			r, ok := m.Results.SelectedItem().(searchResult)
+			if !ok {
+				return m, nil
+			}
+			params := map[string]string{"q": m.Input.Value(), "match": strconv.Itoa(r.Nth)}
+			if r.Slug != "" {
+				params["slug"] = r.Slug
+			}
+			return m, Navigate(r.route(), params)
+		}
+
+		before := m.Input.Value()
+		var cmd tea.Cmd
+		m.Input, cmd = m.Input.Update(msg)
+		if m.Input.Value() != before {
+			return m, tea.Batch(cmd, m.search())
+		}
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+func (m *SearchModel) updateDimensions(width, height int) {
+	m.Width = width
+	m.Height = height
+	m.Help.Width = width
+	m.Input.Width = min(60, width-lipgloss.Width(m.Input.Prompt)-1)
+	m.Results.SetSize(listSize(width, height, 3))
+}
+
+func (m *SearchModel) View() string {
+	m.Help.Styles.ShortDesc = m.th.Faint()
+	m.Help.ShortSeparator = " • "
+
+	body := m.Results.View()
+	if m.shown != "" && m.shown == m.Input.Value() && len(m.Results.Items()) == 0 {
+		body = m.th.Style().Foreground(m.th.Error).Render("\nNo matches\n")
+	}
+	s := fmt.Sprintf("%s\n%s\n%s", m.Input.View(), body, m.Help.View(m.KeyMap))
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, s)
+}
+
+type SearchKeyMap struct{}
+
+func (k SearchKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "choose")),
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}
+}
+
+func (k SearchKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
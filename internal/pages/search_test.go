@@ -0,0 +1,115 @@
+package pages
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"DragonTUI/internal/content"
+	"DragonTUI/internal/theme"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// needlesDoc has a link whose URL repeats the query, and a paragraph that
+// wraps "enough that the words" across two lines at 40 columns.
+const needlesDoc = `# Needles
+
+See [the needle guide](https://example.com/needle) first.
+
+This sentence runs long enough that the words threaded needle sit across a wrapped line.
+
+A *needle* again.
+`
+
+func newNeedlesLibrary(t *testing.T) *content.Library {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "needles.md"), []byte(needlesDoc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lib := content.NewLibrary(dir, theme.Default().Markdown())
+	if err := lib.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	return lib
+}
+
+func newNeedlesSearch(t *testing.T, width, height int) *SearchModel {
+	t.Helper()
+	resume := content.NewStore(func() (string, error) { return "", nil }, theme.Default().Markdown())
+	index := content.NewIndex()
+	index.Rebuild(resume, newNeedlesLibrary(t))
+	search := NewSearchModel(width, height, index)
+	search.SetTheme(theme.Default())
+	return search
+}
+
+func TestSearchResultsOpenTheirMatch(t *testing.T) {
+	const width, height = 40, 20
+	lib := newNeedlesLibrary(t)
+
+	for _, query := range []string{"needle", "enough that the words"} {
+		t.Run(query, func(t *testing.T) {
+			search := newNeedlesSearch(t, width, height)
+			search.Input.SetValue(query)
+			search.Update(search.search()())
+
+			doc := NewDocModel(width, height, lib)
+			doc.SetTheme(theme.Default())
+			doc.Enter(map[string]string{"slug": "needles", "q": query})
+			lines := strings.Split(strings.ToLower(ansi.Strip(doc.rendered)), "\n")
+
+			items := search.Results.Items()
+			if len(items) == 0 {
+				t.Fatal("no results")
+			}
+			if len(items) != len(doc.find.hits) {
+				t.Fatalf("search found %d results, the page highlights %d", len(items), len(doc.find.hits))
+			}
+			first := strings.Fields(query)[0]
+			for _, item := range items {
+				r := item.(searchResult)
+				doc.find.seek(r.Nth)
+				line, ok := doc.find.line()
+				if !ok || doc.find.missing != 0 || doc.find.current != r.Nth {
+					t.Fatalf("result %d did not select a match on the page", r.Nth)
+				}
+				if !strings.Contains(lines[line], first) {
+					t.Errorf("result %d scrolls to line %d, %q", r.Nth, line, lines[line])
+				}
+			}
+		})
+	}
+}
+
+func TestSearchDropsStaleResults(t *testing.T) {
+	search := newNeedlesSearch(t, 40, 20)
+	search.Input.SetValue("needle")
+	stale := search.search()
+	search.Input.SetValue("threaded")
+	search.Update(search.search()())
+	search.Update(stale())
+
+	items := search.Results.Items()
+	if len(items) != 1 {
+		t.Fatalf("got %d results, want the 1 for the current query", len(items))
+	}
+	if got := items[0].(searchResult).Match.Snippet; !strings.Contains(got, "threaded") {
+		t.Errorf("result is %q", got)
+	}
+}
+
+func TestSeekPastTheLastMatch(t *testing.T) {
+	doc := NewDocModel(40, 20, newNeedlesLibrary(t))
+	doc.SetTheme(theme.Default())
+	doc.Enter(map[string]string{"slug": "needles", "q": "needle", "match": "9"})
+
+	if got := doc.find.missing; got != 10 {
+		t.Fatalf("missing = %d, want 10", got)
+	}
+	if _, ok := doc.find.line(); !ok {
+		t.Fatal("the page lost its matches")
+	}
+}
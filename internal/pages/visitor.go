@@ -0,0 +1,65 @@
+package pages
+
+import (
+	"net"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// Visitor identifies who is on the other end of an SSH session.
+type Visitor struct {
+	User           string
+	KeyFingerprint string
+	RemoteAddr     string
+	Admin          bool
+	// UserID is the visitor's user record, zero for anonymous sessions.
+	UserID int64
+	// Name is what the visitor told us to call them, if anything.
+	Name      string
+	Returning bool
+	// Session is the correlation ID of the visitor's session.
+	Session string
+}
+
+// Auditor records what the visitor does in the audit log.
+type Auditor interface {
+	Record(action, detail string)
+}
+
+// recordAudit records action off the UI goroutine. A nil auditor records
+// nothing.
+func recordAudit(a Auditor, action, detail string) tea.Cmd {
+	if a == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		a.Record(action, detail)
+		return nil
+	}
+}
+
+// Greeting is the name to address the visitor by.
+func (v Visitor) Greeting() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return v.User
+}
+
+// IP is the visitor's address without the port.
+func (v Visitor) IP() string {
+	host, _, err := net.SplitHostPort(v.RemoteAddr)
+	if err != nil {
+		return v.RemoteAddr
+	}
+	return host
+}
+
+// Logger logs on behalf of the visitor's session.
+func (v Visitor) Logger() *log.Logger {
+	if v.Session == "" {
+		return log.Default()
+	}
+	return log.With("session", v.Session)
+}
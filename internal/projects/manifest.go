@@ -0,0 +1,130 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a JSON or YAML file listing projects by hand:
+//
+//	projects:
+//	  - name: DragonTUI
+//	    description: My resume over SSH
+//	    language: Go
+//	    stars: 3
+//	    updated: 2024-11-02
+//	    url: https://github.com/you/DragonTUI
+//	    readme: README.md
+//
+// README paths are relative to the manifest. The file is read on every call,
+// so edits show up without a restart.
+type Manifest struct {
+	Path string
+}
+
+type manifestFile struct {
+	Projects []manifestProject `json:"projects" yaml:"projects"`
+}
+
+type manifestProject struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Language    string `json:"language" yaml:"language"`
+	Stars       int    `json:"stars" yaml:"stars"`
+	Updated     string `json:"updated" yaml:"updated"`
+	URL         string `json:"url" yaml:"url"`
+	Readme      string `json:"readme" yaml:"readme"`
+}
+
+func NewManifest(path string) *Manifest {
+	return &Manifest{Path: path}
+}
+
+func (m *Manifest) load() ([]manifestProject, error) {
+	data, err := os.ReadFile(m.Path)
+	if err != nil {
+		return nil, fmt.Errorf("project manifest: %w", err)
+	}
+	var file manifestFile
+	if strings.EqualFold(filepath.Ext(m.Path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("project manifest %s: %w", m.Path, err)
+	}
+	return file.Projects, nil
+}
+
+func (m *Manifest) Projects(ctx context.Context) ([]Project, error) {
+	entries, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]Project, 0, len(entries))
+	for _, e := range entries {
+		p := Project{
+			Name:        e.Name,
+			Description: e.Description,
+			Language:    e.Language,
+			Stars:       e.Stars,
+			URL:         e.URL,
+		}
+		if e.Updated != "" {
+			if p.UpdatedAt, err = parseDate(e.Updated); err != nil {
+				return nil, fmt.Errorf("project manifest %s: %s: %w", m.Path, e.Name, err)
+			}
+		}
+		projects = append(projects, p)
+	}
+	return projects, nil
+}
+
+func (m *Manifest) README(ctx context.Context, name string) (string, error) {
+	entries, err := m.load()
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if e.Name != name {
+			continue
+		}
+		if e.Readme == "" {
+			return "", ErrNoReadme
+		}
+		path := e.Readme
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(m.Path), path)
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", ErrNoReadme
+		}
+		if err != nil {
+			return "", fmt.Errorf("%s README: %w", name, err)
+		}
+		return string(data), nil
+	}
+	return "", ErrProjectNotFound
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("updated %q must be YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
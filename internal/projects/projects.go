@@ -0,0 +1,33 @@
+// Package projects lists the repositories shown on the Projects page. Where
+// they come from is up to the Source: a manifest file on disk or a git
+// server.
+package projects
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type Project struct {
+	Name        string
+	Description string
+	Language    string
+	Stars       int
+	UpdatedAt   time.Time
+	URL         string
+}
+
+// Source provides the projects and their READMEs. Implementations may talk
+// to the network, so pages call them from a tea.Cmd.
+type Source interface {
+	Projects(ctx context.Context) ([]Project, error)
+	// README returns the markdown README of the named project, or
+	// ErrNoReadme if it has none.
+	README(ctx context.Context, name string) (string, error)
+}
+
+var (
+	ErrNoReadme        = errors.New("project has no README")
+	ErrProjectNotFound = errors.New("project not found")
+)
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// AdminStore is the authorized_admins table.
+type AdminStore interface {
+	IsAuthorizedAdmin(ctx context.Context, fingerprint string) (bool, error)
+}
+
+// Admins decides which keys unlock the admin pages. A key is an admin if its
+// fingerprint is in the configuration, in the authorized admins file, or in
+// the database.
+type Admins struct {
+	fingerprints map[string]bool
+	file         string
+	store        AdminStore
+}
+
+func NewAdmins(fingerprints []string, file string, store AdminStore) *Admins {
+	a := &Admins{fingerprints: make(map[string]bool), file: file, store: store}
+	for _, fp := range fingerprints {
+		a.fingerprints[fp] = true
+	}
+	return a
+}
+
+// IsAdmin reports whether fingerprint belongs to an admin. Keyless sessions
+// are never admins. Errors are logged and treated as "no".
+func (a *Admins) IsAdmin(ctx context.Context, fingerprint string) bool {
+	if fingerprint == "" {
+		return false
+	}
+	if a.fingerprints[fingerprint] {
+		return true
+	}
+	if a.file != "" {
+		ok, err := fileHasFingerprint(a.file, fingerprint)
+		if err != nil {
+			log.Error("Could not read admins file", "path", a.file, "err", err)
+		}
+		if ok {
+			return true
+		}
+	}
+	if a.store != nil {
+		ok, err := a.store.IsAuthorizedAdmin(ctx, fingerprint)
+		if err != nil {
+			log.Error("Could not check admin", "key", fingerprint, "err", err)
+		}
+		return ok
+	}
+	return false
+}
+
+// fileHasFingerprint reads an authorized admins file, which takes either
+// SHA256 fingerprints or authorized_keys lines, one per line. The file is
+// read on every check so edits apply to the next session; a missing file
+// authorizes nobody.
+func fileHasFingerprint(path, fingerprint string) (bool, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "SHA256:") {
+			if strings.Fields(line)[0] == fingerprint {
+				return true, nil
+			}
+			continue
+		}
+		key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			continue
+		}
+		if gossh.FingerprintSHA256(key) == fingerprint {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"DragonTUI/internal/db"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// AuditStore keeps the audit log.
+type AuditStore interface {
+	RecordAudit(ctx context.Context, e db.AuditEntry) error
+}
+
+// Auditor logs what one session does and records it in the audit log. A nil
+// *Auditor does neither.
+type Auditor struct {
+	store  AuditStore
+	logger *log.Logger
+	entry  db.AuditEntry
+}
+
+// Record notes that the session did action. Detail says to what, such as
+// the page opened or the session an admin disconnected.
+func (a *Auditor) Record(action, detail string) {
+	if a == nil {
+		return
+	}
+	a.logger.Debug("Audit", "action", action, "detail", detail)
+	if a.store == nil {
+		return
+	}
+	e := a.entry
+	e.Action, e.Detail = action, detail
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.store.RecordAudit(ctx, e); err != nil {
+		a.logger.Error("Could not record audit entry", "action", action, "err", err)
+	}
+}
+
+type sessionLogKey struct{}
+
+type sessionLog struct {
+	id      string
+	logger  *log.Logger
+	auditor *Auditor
+}
+
+func sessionLogOf(sess ssh.Session) sessionLog {
+	l, _ := sess.Context().Value(sessionLogKey{}).(sessionLog)
+	return l
+}
+
+// CorrelationID returns the random ID that tags the log lines and audit
+// entries of sess. Unlike SessionID it is unique across restarts.
+func CorrelationID(sess ssh.Session) string {
+	return sessionLogOf(sess).id
+}
+
+// SessionLogger returns a logger that tags every line with the correlation
+// ID of sess.
+func SessionLogger(sess ssh.Session) *log.Logger {
+	if l := sessionLogOf(sess).logger; l != nil {
+		return l
+	}
+	return log.Default()
+}
+
+// SessionAuditor returns the auditor of sess.
+func SessionAuditor(sess ssh.Session) *Auditor {
+	return sessionLogOf(sess).auditor
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// auditMiddleware gives every session a correlation ID, a logger and an
+// auditor, and records when it connects and disconnects.
+func auditMiddleware(store AuditStore) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			id := newCorrelationID()
+			fingerprint := KeyFingerprint(sess)
+			logger := log.With("session", id)
+			l := sessionLog{
+				id:     id,
+				logger: logger,
+				auditor: &Auditor{
+					store:  store,
+					logger: logger,
+					entry: db.AuditEntry{
+						Session:     id,
+						Fingerprint: fingerprint,
+						RemoteAddr:  sess.RemoteAddr().String(),
+					},
+				},
+			}
+			sess.Context().SetValue(sessionLogKey{}, l)
+
+			pty, _, _ := sess.Pty()
+			logger.Info("Connect", "user", sess.User(), "addr", sess.RemoteAddr().String(), "key", fingerprint,
+				"term", pty.Term, "width", pty.Window.Width, "height", pty.Window.Height)
+			l.auditor.Record("connect", sess.User())
+
+			start := time.Now()
+			next(sess)
+
+			duration := time.Since(start).Round(time.Millisecond)
+			logger.Info("Disconnect", "duration", duration)
+			l.auditor.Record("disconnect", duration.String())
+		}
+	}
+}
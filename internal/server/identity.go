@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"DragonTUI/internal/db"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// IdentityStore maps public keys to user records.
+type IdentityStore interface {
+	RecordVisit(ctx context.Context, fingerprint, username string) (*db.User, error)
+}
+
+// Identity is who is on the other end of a session. Anonymous sessions, those
+// that signed in without a key, have no fingerprint and no user record.
+type Identity struct {
+	User        *db.User
+	Fingerprint string
+	Username    string
+}
+
+// Returning reports whether the visitor has connected with this key before.
+func (i Identity) Returning() bool {
+	return i.User != nil && i.User.Visits > 1
+}
+
+// Name is what to call the visitor: the name they gave us, or their SSH
+// user name.
+func (i Identity) Name() string {
+	if i.User != nil && i.User.Name != "" {
+		return i.User.Name
+	}
+	return i.Username
+}
+
+type identityKey struct{}
+
+// KeyFingerprint returns the SHA256 fingerprint of the public key the session
+// authenticated with, or an empty string for keyless sessions.
+func KeyFingerprint(sess ssh.Session) string {
+	if sess.PublicKey() == nil {
+		return ""
+	}
+	return gossh.FingerprintSHA256(sess.PublicKey())
+}
+
+// SessionIdentity returns the identity the identity middleware attached to
+// sess.
+func SessionIdentity(sess ssh.Session) Identity {
+	if id, ok := sess.Context().Value(identityKey{}).(Identity); ok {
+		return id
+	}
+	return Identity{Fingerprint: KeyFingerprint(sess), Username: sess.User()}
+}
+
+// identityMiddleware records the visit of every keyed session and attaches
+// the visitor's identity to the session context. A database error costs the
+// visitor their greeting, not their session.
+func identityMiddleware(store IdentityStore) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			id := Identity{Fingerprint: KeyFingerprint(sess), Username: sess.User()}
+			if id.Fingerprint != "" && store != nil {
+				ctx, cancel := context.WithTimeout(sess.Context(), 5*time.Second)
+				user, err := store.RecordVisit(ctx, id.Fingerprint, id.Username)
+				cancel()
+				if err != nil {
+					SessionLogger(sess).Error("Failed to record visit", "key", id.Fingerprint, "err", err)
+				}
+				id.User = user
+			}
+			sess.Context().SetValue(identityKey{}, id)
+			next(sess)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// RateLimiter limits how fast one address can open sessions and how many
+// sessions an address or a key can hold open at once. Zero disables a limit.
+type RateLimiter struct {
+	connectionsPerMinute int
+	sessionsPerIP        int
+	sessionsPerKey       int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	active    map[string]int
+	lastPrune time.Time
+}
+
+// bucket is a token bucket holding up to connectionsPerMinute tokens and
+// refilled at that rate.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(connectionsPerMinute, sessionsPerIP, sessionsPerKey int) *RateLimiter {
+	return &RateLimiter{
+		connectionsPerMinute: connectionsPerMinute,
+		sessionsPerIP:        sessionsPerIP,
+		sessionsPerKey:       sessionsPerKey,
+		buckets:              make(map[string]*bucket),
+		active:               make(map[string]int),
+	}
+}
+
+// acquire admits a session from ip, authenticated with the key fingerprint
+// fp if any. It returns why the session was refused, or a release func to
+// call when the session ends.
+func (l *RateLimiter) acquire(ip, fp string) (release func(), refused string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	if l.connectionsPerMinute > 0 {
+		b, ok := l.buckets[ip]
+		if !ok {
+			b = &bucket{tokens: float64(l.connectionsPerMinute), last: now}
+			l.buckets[ip] = b
+		}
+		b.tokens = min(float64(l.connectionsPerMinute), b.tokens+now.Sub(b.last).Minutes()*float64(l.connectionsPerMinute))
+		b.last = now
+		if b.tokens < 1 {
+			return nil, "Too many connections, slow down and try again in a minute."
+		}
+		b.tokens--
+	}
+
+	ipKey, fpKey := "ip:"+ip, "key:"+fp
+	if l.sessionsPerIP > 0 && l.active[ipKey] >= l.sessionsPerIP {
+		return nil, fmt.Sprintf("Only %d sessions per address at a time, close one and try again.", l.sessionsPerIP)
+	}
+	if fp != "" && l.sessionsPerKey > 0 && l.active[fpKey] >= l.sessionsPerKey {
+		return nil, fmt.Sprintf("Only %d sessions per key at a time, close one and try again.", l.sessionsPerKey)
+	}
+	l.active[ipKey]++
+	if fp != "" {
+		l.active[fpKey]++
+	}
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.decrement(ipKey)
+		if fp != "" {
+			l.decrement(fpKey)
+		}
+	}, ""
+}
+
+func (l *RateLimiter) decrement(key string) {
+	if l.active[key]--; l.active[key] <= 0 {
+		delete(l.active, key)
+	}
+}
+
+// prune forgets the buckets that have refilled, at most once a minute, so
+// the map doesn't grow with every address that ever connected.
+func (l *RateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+	for ip, b := range l.buckets {
+		if now.Sub(b.last) >= time.Minute {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+// middleware turns away sessions over the limits before they reach the
+// rest of the stack.
+func (l *RateLimiter) middleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(sess ssh.Session) {
+			ip := sess.RemoteAddr().String()
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+			release, refused := l.acquire(ip, KeyFingerprint(sess))
+			if refused != "" {
+				SessionLogger(sess).Warn("Refused session", "addr", sess.RemoteAddr().String(), "reason", refused)
+				wish.Fatalln(sess, refused)
+				return
+			}
+			defer release()
+			next(sess)
+		}
+	}
+}
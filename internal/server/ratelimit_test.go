@@ -0,0 +1,143 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	// A step opens a session from ip with key fp, or, when release is set,
+	// ends the session opened at that step (counting from 1).
+	type step struct {
+		ip, fp  string
+		release int
+		refused string
+	}
+	tests := []struct {
+		name                                     string
+		perMinute, sessionsPerIP, sessionsPerKey int
+		steps                                    []step
+	}{
+		{
+			name:      "bucket runs dry",
+			perMinute: 2,
+			steps: []step{
+				{ip: "10.0.0.1"},
+				{ip: "10.0.0.1"},
+				{ip: "10.0.0.1", refused: "Too many connections"},
+				{ip: "10.0.0.2"},
+			},
+		},
+		{
+			name:      "closing sessions does not refill the bucket",
+			perMinute: 1,
+			steps: []step{
+				{ip: "10.0.0.1"},
+				{release: 1},
+				{ip: "10.0.0.1", refused: "Too many connections"},
+			},
+		},
+		{
+			name:          "sessions per address",
+			sessionsPerIP: 2,
+			steps: []step{
+				{ip: "10.0.0.1", fp: "a"},
+				{ip: "10.0.0.1", fp: "b"},
+				{ip: "10.0.0.1", fp: "c", refused: "Only 2 sessions per address"},
+				{ip: "10.0.0.2", fp: "c"},
+				{release: 1},
+				{ip: "10.0.0.1", fp: "c"},
+			},
+		},
+		{
+			name:           "sessions per key",
+			sessionsPerKey: 1,
+			steps: []step{
+				{ip: "10.0.0.1", fp: "a"},
+				{ip: "10.0.0.2", fp: "a", refused: "Only 1 sessions per key"},
+				{ip: "10.0.0.2", fp: "b"},
+				{ip: "10.0.0.2"},
+				{ip: "10.0.0.3"},
+				{release: 1},
+				{ip: "10.0.0.2", fp: "a"},
+			},
+		},
+		{
+			name:          "refused sessions hold no slot",
+			perMinute:     2,
+			sessionsPerIP: 1,
+			steps: []step{
+				{ip: "10.0.0.1"},
+				{ip: "10.0.0.1", refused: "Only 1 sessions per address"},
+				{release: 1},
+				{ip: "10.0.0.1", refused: "Too many connections"},
+			},
+		},
+		{
+			name: "no limits",
+			steps: []step{
+				{ip: "10.0.0.1", fp: "a"},
+				{ip: "10.0.0.1", fp: "a"},
+				{ip: "10.0.0.1", fp: "a"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(tt.perMinute, tt.sessionsPerIP, tt.sessionsPerKey)
+			releases := make(map[int]func())
+			for i, s := range tt.steps {
+				n := i + 1
+				if s.release > 0 {
+					releases[s.release]()
+					continue
+				}
+				release, refused := l.acquire(s.ip, s.fp)
+				if !strings.HasPrefix(refused, s.refused) || (s.refused == "") != (refused == "") {
+					t.Fatalf("step %d: refused %q, want %q", n, refused, s.refused)
+				}
+				if refused == "" {
+					releases[n] = release
+				}
+			}
+			for _, release := range releases {
+				release()
+			}
+			if len(l.active) != 0 {
+				t.Errorf("active sessions left after releasing them all: %v", l.active)
+			}
+		})
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	l := NewRateLimiter(60, 0, 0)
+	for range 60 {
+		if _, refused := l.acquire("10.0.0.1", ""); refused != "" {
+			t.Fatal(refused)
+		}
+	}
+	if _, refused := l.acquire("10.0.0.1", ""); refused == "" {
+		t.Fatal("the 61st connection in a minute was let in")
+	}
+
+	// A second later the bucket holds one more token.
+	l.buckets["10.0.0.1"].last = time.Now().Add(-time.Second)
+	if _, refused := l.acquire("10.0.0.1", ""); refused != "" {
+		t.Fatalf("refused after a second: %s", refused)
+	}
+	if _, refused := l.acquire("10.0.0.1", ""); refused == "" {
+		t.Fatal("the bucket refilled more than one token a second")
+	}
+
+	// Buckets that have had a minute to refill are forgotten.
+	l.buckets["10.0.0.1"].last = time.Now().Add(-time.Minute)
+	l.lastPrune = time.Time{}
+	if _, refused := l.acquire("10.0.0.2", ""); refused != "" {
+		t.Fatal(refused)
+	}
+	if _, ok := l.buckets["10.0.0.1"]; ok {
+		t.Error("a refilled bucket was not pruned")
+	}
+}
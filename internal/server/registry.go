@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// SessionInfo describes a connected visitor.
+type SessionInfo struct {
+	ID uint64
+	// CorrelationID tags the session's log lines and audit entries.
+	CorrelationID string
+	User          string
+	Fingerprint   string
+	RemoteAddr    string
+	// Term is the terminal type the client asked for, such as xterm.
+	Term   string
+	Width  int
+	Height int
+	// Page is the route the visitor is on.
+	Page        string
+	ConnectedAt time.Time
+}
+
+type liveSession struct {
+	info SessionInfo
+	sess ssh.Session
+	// program is nil until the session's Bubble Tea program is created.
+	program *tea.Program
+}
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type sessionIDKey struct{}
+
+// SessionID returns the registry ID of sess, or zero if it isn't
+// registered.
+func SessionID(sess ssh.Session) uint64 {
+	id, _ := sess.Context().Value(sessionIDKey{}).(uint64)
+	return id
+}
+
+// ShutdownMsg is broadcast to every session when the server starts shutting
+// down. Sessions are closed at Deadline.
+type ShutdownMsg struct {
+	Deadline time.Time
+}
+
+// Registry keeps track of the Bubble Tea program behind every open session,
+// so messages can be sent to all of them, and of work that has to finish
+// before the server stops.
+type Registry struct {
+	mu       sync.RWMutex
+	nextID   uint64
+	sessions map[uint64]*liveSession
+	work     sync.WaitGroup
+	// serving is true while the SSH listener takes connections.
+	serving atomic.Bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{sessions: make(map[uint64]*liveSession)}
+}
+
+func (r *Registry) add(sess ssh.Session) uint64 {
+	id := SessionIdentity(sess)
+	pty, _, _ := sess.Pty()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.sessions[r.nextID] = &liveSession{
+		info: SessionInfo{
+			ID:            r.nextID,
+			CorrelationID: CorrelationID(sess),
+			User:          sess.User(),
+			Fingerprint:   id.Fingerprint,
+			RemoteAddr:    sess.RemoteAddr().String(),
+			Term:          pty.Term,
+			Width:         pty.Window.Width,
+			Height:        pty.Window.Height,
+			ConnectedAt:   time.Now(),
+		},
+		sess: sess,
+	}
+	return r.nextID
+}
+
+// update applies fn to the session with the given id, if it is still open.
+func (r *Registry) update(id uint64, fn func(s *liveSession)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.sessions[id]; ok {
+		fn(s)
+	}
+}
+
+// SetPage records the page session id is on.
+func (r *Registry) SetPage(id uint64, page string) {
+	r.update(id, func(s *liveSession) { s.info.Page = page })
+}
+
+// SetWindow records the window size of session id.
+func (r *Registry) SetWindow(id uint64, width, height int) {
+	r.update(id, func(s *liveSession) { s.info.Width, s.info.Height = width, height })
+}
+
+func (r *Registry) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// Serving reports whether the SSH server is taking connections. It turns
+// false as soon as shutdown starts.
+func (r *Registry) Serving() bool {
+	return r.serving.Load()
+}
+
+// Count is the number of open sessions.
+func (r *Registry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.sessions)
+}
+
+// Disconnect ends session id, letting its program say goodbye.
+func (r *Registry) Disconnect(id uint64) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if s.program == nil {
+		return s.sess.Close()
+	}
+	go s.program.Quit()
+	return nil
+}
+
+// Sessions lists the open sessions, oldest first.
+func (r *Registry) Sessions() []SessionInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	infos := make([]SessionInfo, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		infos = append(infos, s.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Broadcast sends msg to every open session. Send blocks until a program
+// takes the message, so each one gets its own goroutine.
+func (r *Registry) Broadcast(msg tea.Msg) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.sessions {
+		if s.program != nil {
+			go s.program.Send(msg)
+		}
+	}
+}
+
+// Hold registers work, such as saving a contact message, that shutdown
+// waits for. Call the returned func when the work is done.
+func (r *Registry) Hold() func() {
+	r.work.Add(1)
+	var once sync.Once
+	return func() { once.Do(r.work.Done) }
+}
+
+// drain waits for held work to finish or ctx to end, whichever is first.
+func (r *Registry) drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.work.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitEmpty returns once every session has closed or ctx ends.
+func (r *Registry) waitEmpty(ctx context.Context) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for r.Count() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// quit ends the program of every open session, which closes the sessions.
+func (r *Registry) quit() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.sessions {
+		if s.program != nil {
+			go s.program.Quit()
+		} else {
+			s.sess.Close()
+		}
+	}
+}
+
+// middleware runs teaHandler's model as a program registered for the
+// lifetime of the session. The session is registered before teaHandler runs,
+// so the handler can look up its ID with SessionID.
+func (r *Registry) middleware(teaHandler func(ssh.Session) (tea.Model, []tea.ProgramOption)) wish.Middleware {
+	return bubbletea.MiddlewareWithProgramHandler(func(sess ssh.Session) *tea.Program {
+		id := r.add(sess)
+		sess.Context().SetValue(sessionIDKey{}, id)
+		go func() {
+			<-sess.Context().Done()
+			r.remove(id)
+		}()
+
+		model, opts := teaHandler(sess)
+		if model == nil {
+			return nil
+		}
+		// The server's own signal handling decides when sessions end; a
+		// program handling SIGTERM itself would quit before visitors are
+		// warned.
+		opts = append(opts, tea.WithoutSignalHandler())
+		program := tea.NewProgram(model, append(opts, bubbletea.MakeOptions(sess)...)...)
+		r.update(id, func(s *liveSession) { s.program = program })
+		return program
+	}, termenv.Ascii)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// startServer serves sessions through middleware on a random local port,
+// letting in any key.
+func startServer(t *testing.T, middleware ...wish.Middleware) string {
+	t.Helper()
+	srv, err := wish.NewServer(
+		wish.WithHostKeyPath(filepath.Join(t.TempDir(), "host_ed25519")),
+		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool { return true }),
+		wish.WithMiddleware(middleware...),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+	return ln.Addr().String()
+}
+
+// runSession opens a session to addr as user with a fresh key and waits
+// for the server to end it.
+func runSession(t *testing.T, addr, user string) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		User:            user,
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(signer)},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	session, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	session.Run("")
+}
+
+func TestRegistryRecordsCorrelationID(t *testing.T) {
+	r := NewRegistry()
+	type seen struct {
+		want string
+		info SessionInfo
+	}
+	got := make(chan seen, 1)
+	addr := startServer(t,
+		func(next ssh.Handler) ssh.Handler {
+			return func(sess ssh.Session) {
+				id := r.add(sess)
+				defer r.remove(id)
+				got <- seen{want: CorrelationID(sess), info: r.Sessions()[0]}
+			}
+		},
+		auditMiddleware(nil),
+	)
+
+	runSession(t, addr, "visitor")
+	s := <-got
+	if s.want == "" {
+		t.Fatal("the audit middleware gave the session no correlation ID")
+	}
+	if s.info.CorrelationID != s.want {
+		t.Errorf("CorrelationID = %q, want %q", s.info.CorrelationID, s.want)
+	}
+	if s.info.User != "visitor" || s.info.Fingerprint == "" {
+		t.Errorf("SessionInfo = %+v", s.info)
+	}
+}
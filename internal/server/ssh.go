@@ -4,56 +4,122 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"DragonTUI/internal/config"
+
 	tea "github.com/charmbracelet/bubbletea"
-	cl "github.com/charmbracelet/log"
+	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/activeterm"
-	"github.com/charmbracelet/wish/bubbletea"
-	lm "github.com/charmbracelet/wish/logging"
+	gossh "golang.org/x/crypto/ssh"
 )
 
-func InitServer(host string, port int, teaHandler func(ssh.Session) (tea.Model, []tea.ProgramOption)) {
-	s, err := wish.NewServer(wish.WithAddress(fmt.Sprintf("%s:%d", host, port)),
-		wish.WithHostKeyPath(".ssh/term_info_ed25519"),
+// InitServer serves teaHandler over SSH until the process is interrupted.
+// Every public key is accepted, since keys are how returning visitors are
+// recognised; visitors without one get in through keyboard-interactive only
+// when cfg.AnonymousAccess is set. Sessions over limiter's limits are turned
+// away before anything else runs, though not before they are given the
+// correlation ID that tags their log lines and audit entries; metrics, which
+// may be nil, sees the rest.
+//
+// On SIGINT or SIGTERM the server stops taking connections, warns every
+// session that it is restarting and gives visitors cfg.ShutdownGrace to
+// finish. It then waits for contact submissions in flight to be saved and
+// closes whatever sessions remain. A second signal skips the wait.
+func InitServer(cfg config.ServerConfig, identities IdentityStore, audit AuditStore, registry *Registry, limiter *RateLimiter, metrics wish.Middleware, teaHandler func(ssh.Session) (tea.Model, []tea.ProgramOption)) {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	if metrics == nil {
+		metrics = func(next ssh.Handler) ssh.Handler { return next }
+	}
+	opts := []ssh.Option{
+		wish.WithAddress(addr),
+		wish.WithHostKeyPath(cfg.HostKeyPath),
+		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
+			return true
+		}),
+		// Middleware runs last to first: the limiter turns sessions away
+		// before they are audited, greeted or recorded as visits.
 		wish.WithMiddleware(
 			func(next ssh.Handler) ssh.Handler {
 				return func(sess ssh.Session) {
-					wish.Println(sess, fmt.Sprintf("\x1B[1;31mBye, %s!\x1B[0m", sess.User()))
+					wish.Println(sess, fmt.Sprintf("\x1B[1;31mBye, %s!\x1B[0m", SessionIdentity(sess).Name()))
 					next(sess)
 				}
 			},
-			bubbletea.Middleware(teaHandler),
+			registry.middleware(teaHandler),
+			identityMiddleware(identities),
 			activeterm.Middleware(),
-			lm.Middleware(),
+			metrics,
+			auditMiddleware(audit),
+			limiter.middleware(),
 		),
-	)
+	}
+	if cfg.AnonymousAccess {
+		opts = append(opts, wish.WithKeyboardInteractiveAuth(func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+			return true
+		}))
+	}
+	s, err := wish.NewServer(opts...)
 	if err != nil {
-		log.Fatalln(err)
+		log.Fatal("Could not create server", "err", err)
 	}
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
-		log.Printf("Starting SSH server on %s:%d", host, port)
-		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
-			cl.Error("Could not start server", "error", err)
+		log.Info("Starting SSH server", "addr", addr)
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Error("Could not start server", "err", err)
+			done <- nil
+			return
+		}
+		registry.serving.Store(true)
+		if err = s.Serve(ln); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			log.Error("Server failed", "err", err)
 			done <- nil
 		}
 	}()
 
 	<-done
-	log.Println("Stopping SSH server")
+	log.Info("Stopping SSH server")
+	registry.serving.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGrace+30*time.Second)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			log.Info("Stopping SSH server now")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Shutdown closes the listeners straight away and then waits for the
+	// open sessions, which are closed below.
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Shutdown(ctx) }()
+
+	deadline := time.Now().Add(cfg.ShutdownGrace)
+	registry.Broadcast(ShutdownMsg{Deadline: deadline})
+	graceCtx, cancelGrace := context.WithDeadline(ctx, deadline)
+	registry.waitEmpty(graceCtx)
+	cancelGrace()
+
+	if err := registry.drain(ctx); err != nil {
+		log.Error("Gave up waiting for contact submissions", "err", err)
+	}
+	registry.quit()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer func() { cancel() }()
-	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
-		cl.Error("Could not stop server", "error", err)
+	if err := <-stopped; err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+		log.Error("Could not stop server", "err", err)
 	}
 }
@@ -0,0 +1,176 @@
+// Package softserve talks to a soft-serve git server through its SSH command
+// interface, the same commands a user would type after
+// `ssh -p 23231 host repo ...`.
+package softserve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type Config struct {
+	// Addr is the host:port of soft-serve's SSH listener.
+	Addr string
+	User string
+	// KeyPath is the private key to authenticate with. Anonymous read
+	// access is enough for public repositories.
+	KeyPath string
+	// KnownHostsPath verifies the server's host key. It is required unless
+	// InsecureSkipHostKey is set, which is only reasonable for a server on
+	// the same host or docker network.
+	KnownHostsPath      string
+	InsecureSkipHostKey bool
+	Timeout             time.Duration
+}
+
+// Client runs soft-serve commands, one SSH session per command.
+type Client struct {
+	addr    string
+	config  *ssh.ClientConfig
+	timeout time.Duration
+}
+
+// CommandError is returned when soft-serve ran a command and it failed, as
+// opposed to a connection problem.
+type CommandError struct {
+	Command string
+	Stderr  string
+}
+
+func (e *CommandError) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		msg = "command failed"
+	}
+	return fmt.Sprintf("soft-serve %s: %s", e.Command, msg)
+}
+
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("soft-serve address is required")
+	}
+	clientConfig := &ssh.ClientConfig{User: cfg.User}
+	if cfg.KeyPath != "" {
+		// The key is read when connecting, so a host key that the SSH
+		// server generates on first start is picked up once it exists.
+		clientConfig.Auth = append(clientConfig.Auth, ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+			pem, err := os.ReadFile(cfg.KeyPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("soft-serve key: %w", err)
+			}
+			signer, err := ssh.ParsePrivateKey(pem)
+			if err != nil {
+				return nil, fmt.Errorf("soft-serve key %s: %w", cfg.KeyPath, err)
+			}
+			return []ssh.Signer{signer}, nil
+		}))
+	}
+	// soft-serve lets keyless clients in through keyboard-interactive when
+	// anonymous access is enabled.
+	clientConfig.Auth = append(clientConfig.Auth, ssh.KeyboardInteractive(
+		func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+			return make([]string, len(questions)), nil
+		}))
+
+	switch {
+	case cfg.KnownHostsPath != "":
+		callback, err := knownhosts.New(cfg.KnownHostsPath)
+		if err != nil {
+			return nil, fmt.Errorf("soft-serve known hosts: %w", err)
+		}
+		clientConfig.HostKeyCallback = callback
+	case cfg.InsecureSkipHostKey:
+		log.Warn("Not verifying soft-serve's host key", "addr", cfg.Addr)
+		clientConfig.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, errors.New("soft-serve known hosts are required to verify its host key, or skip the check explicitly")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	clientConfig.Timeout = timeout
+	return &Client{addr: cfg.Addr, config: clientConfig, timeout: timeout}, nil
+}
+
+// Run executes one soft-serve command and returns its standard output.
+func (c *Client) Run(ctx context.Context, args ...string) (string, error) {
+	command := quoteArgs(args)
+
+	var d net.Dialer
+	dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	conn, err := d.DialContext(dialCtx, "tcp", c.addr)
+	if err != nil {
+		return "", fmt.Errorf("soft-serve: %w", err)
+	}
+	// Closing the connection is what interrupts a command when ctx is
+	// cancelled; the ssh package has no context support of its own.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// ssh.ClientConfig.Timeout only applies to ssh.Dial, so the handshake
+	// gets a deadline of its own.
+	conn.SetDeadline(time.Now().Add(c.timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, c.addr, c.config)
+	if err != nil {
+		conn.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("soft-serve: %w", err)
+	}
+	conn.SetDeadline(time.Time{})
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("soft-serve: %w", err)
+	}
+	defer sess.Close()
+
+	var stdout, stderr bytes.Buffer
+	sess.Stdout = &stdout
+	sess.Stderr = &stderr
+	if err := sess.Run(command); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return "", &CommandError{Command: command, Stderr: stderr.String()}
+		}
+		return "", fmt.Errorf("soft-serve %s: %w", command, err)
+	}
+	return stdout.String(), nil
+}
+
+// quoteArgs joins args into a command line, single-quoting anything the
+// server's shell-style splitter would otherwise break apart.
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a != "" && !strings.ContainsAny(a, " \t\n'\"\\$`") {
+			quoted[i] = a
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
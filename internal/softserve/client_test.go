@@ -0,0 +1,251 @@
+package softserve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"DragonTUI/internal/projects"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// fixtureRepos is what the fake soft-serve prints for each command line.
+// Commands missing from it fail the way soft-serve does, with a message on
+// stderr and a non-zero exit status.
+var fixtureRepos = map[string]string{
+	"repo list":                  "dragon\nlair\n",
+	"repo info dragon":           "Project Name: Dragon\nRepository: dragon\nDescription: A terminal portfolio\nPrivate: false\nDefault Branch: main\n",
+	"repo blob dragon README.md": "# Dragon\n",
+}
+
+// hang is a command the fake soft-serve never answers.
+const hang = "repo hang"
+
+// startServer serves commands over SSH on a random local port, letting
+// anyone in through keyboard-interactive as anonymous soft-serve does.
+func startServer(t *testing.T, commands map[string]string) string {
+	t.Helper()
+	srv, err := wish.NewServer(
+		wish.WithHostKeyPath(filepath.Join(t.TempDir(), "host_ed25519")),
+		wish.WithKeyboardInteractiveAuth(func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+			return true
+		}),
+		wish.WithMiddleware(func(next ssh.Handler) ssh.Handler {
+			return func(sess ssh.Session) {
+				command := strings.Join(sess.Command(), " ")
+				if command == hang {
+					<-sess.Context().Done()
+					return
+				}
+				out, ok := commands[command]
+				if !ok {
+					fmt.Fprintf(sess.Stderr(), "Error: %s: not found\n", command)
+					sess.Exit(1)
+					return
+				}
+				fmt.Fprint(sess, out)
+				sess.Exit(0)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+	return ln.Addr().String()
+}
+
+func newTestSource(t *testing.T, addr string, timeout time.Duration) *Source {
+	t.Helper()
+	client, err := NewClient(Config{Addr: addr, User: "visitor", InsecureSkipHostKey: true, Timeout: timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSource(client)
+}
+
+func TestSourceListsFixtureRepos(t *testing.T) {
+	src := newTestSource(t, startServer(t, fixtureRepos), 5*time.Second)
+	ctx := context.Background()
+
+	got, err := src.Projects(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// lair has no info, which leaves it without a description rather than
+	// failing the listing.
+	want := []projects.Project{
+		{Name: "dragon", Description: "A terminal portfolio"},
+		{Name: "lair"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Projects() = %+v, want %+v", got, want)
+	}
+
+	info, err := src.Info(ctx, "dragon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ProjectName != "Dragon" || info.DefaultBranch != "main" || info.Private {
+		t.Errorf("Info() = %+v", info)
+	}
+
+	readme, err := src.README(ctx, "dragon")
+	if err != nil || readme != "# Dragon\n" {
+		t.Errorf("README(dragon) = %q, %v", readme, err)
+	}
+	if _, err := src.README(ctx, "lair"); !errors.Is(err, projects.ErrNoReadme) {
+		t.Errorf("README(lair) error = %v, want %v", err, projects.ErrNoReadme)
+	}
+}
+
+func TestClientCommandError(t *testing.T) {
+	src := newTestSource(t, startServer(t, fixtureRepos), 5*time.Second)
+
+	_, err := src.Info(context.Background(), "missing")
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Info(missing) error = %v, want a CommandError", err)
+	}
+	if want := "soft-serve repo info missing: Error: repo info missing: not found"; cmdErr.Error() != want {
+		t.Errorf("error = %q, want %q", cmdErr.Error(), want)
+	}
+}
+
+func TestClientTimesOut(t *testing.T) {
+	src := newTestSource(t, startServer(t, fixtureRepos), 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := src.Run(ctx, "repo", "hang")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Run took %s to give up", elapsed)
+	}
+}
+
+func TestClientHandshakeTimesOut(t *testing.T) {
+	// A listener that accepts connections but never says anything.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		<-done
+		conn.Close()
+	}()
+
+	start := time.Now()
+	_, err = newTestSource(t, ln.Addr().String(), 200*time.Millisecond).Repos(context.Background())
+	if err == nil {
+		t.Fatal("Repos() succeeded against a silent server")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Repos() took %s to give up", elapsed)
+	}
+}
+
+func TestClientUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	_, err = newTestSource(t, addr, time.Second).Projects(context.Background())
+	var cmdErr *CommandError
+	if err == nil || errors.As(err, &cmdErr) {
+		t.Fatalf("Projects() error = %v, want a connection error", err)
+	}
+}
+
+// hostKey fetches the host key the server at addr presents.
+func hostKey(t *testing.T, addr string) gossh.PublicKey {
+	t.Helper()
+	var key gossh.PublicKey
+	gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		HostKeyCallback: func(hostname string, remote net.Addr, k gossh.PublicKey) error {
+			key = k
+			return errors.New("only looking")
+		},
+		Timeout: 5 * time.Second,
+	})
+	if key == nil {
+		t.Fatalf("no host key from %s", addr)
+	}
+	return key
+}
+
+func TestClientHostKey(t *testing.T) {
+	addr := startServer(t, fixtureRepos)
+	other := hostKey(t, startServer(t, fixtureRepos))
+	writeKnownHosts := func(host string, key gossh.PublicKey) string {
+		path := filepath.Join(t.TempDir(), "known_hosts")
+		line := knownhosts.Line([]string{knownhosts.Normalize(host)}, key) + "\n"
+		if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name       string
+		knownHosts string
+		skip       bool
+		wantNew    string
+		wantRun    string
+	}{
+		{name: "known host", knownHosts: writeKnownHosts(addr, hostKey(t, addr))},
+		{name: "changed host key", knownHosts: writeKnownHosts(addr, other), wantRun: "key mismatch"},
+		{name: "unknown host", knownHosts: writeKnownHosts("example.com", other), wantRun: "key is unknown"},
+		{name: "missing known hosts", knownHosts: filepath.Join(t.TempDir(), "missing"), wantNew: "no such file"},
+		{name: "check skipped", skip: true},
+		{name: "known hosts required", wantNew: "known hosts are required"},
+		{name: "known hosts win over skipping", knownHosts: writeKnownHosts(addr, other), skip: true, wantRun: "key mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(Config{Addr: addr, User: "visitor", KnownHostsPath: tt.knownHosts, InsecureSkipHostKey: tt.skip, Timeout: 5 * time.Second})
+			if tt.wantNew != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantNew) {
+					t.Fatalf("NewClient() error = %v, want %q", err, tt.wantNew)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = client.Run(context.Background(), "repo", "list")
+			if tt.wantRun == "" && err != nil || tt.wantRun != "" && (err == nil || !strings.Contains(err.Error(), tt.wantRun)) {
+				t.Errorf("Run() error = %v, want %q", err, tt.wantRun)
+			}
+		})
+	}
+}
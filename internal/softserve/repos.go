@@ -0,0 +1,134 @@
+package softserve
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+)
+
+type RepoInfo struct {
+	Name          string
+	ProjectName   string
+	Description   string
+	DefaultBranch string
+	Private       bool
+}
+
+// TreeEntry is one line of `repo tree`.
+type TreeEntry struct {
+	Name string
+	Mode string
+	// Size is soft-serve's human readable size, "-" for directories.
+	Size string
+	Dir  bool
+}
+
+// Repos lists the repositories visible to the client.
+func (c *Client) Repos(ctx context.Context) ([]string, error) {
+	out, err := c.Run(ctx, "repo", "list")
+	if err != nil {
+		return nil, err
+	}
+	return lines(out), nil
+}
+
+// Info parses the "Key: value" lines printed by `repo info`.
+func (c *Client) Info(ctx context.Context, repo string) (RepoInfo, error) {
+	out, err := c.Run(ctx, "repo", "info", repo)
+	if err != nil {
+		return RepoInfo{}, err
+	}
+	info := RepoInfo{Name: repo}
+	for _, line := range lines(out) {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "project name":
+			info.ProjectName = value
+		case "description":
+			info.Description = value
+		case "default branch":
+			info.DefaultBranch = value
+		case "private":
+			info.Private = value == "true"
+		}
+	}
+	return info, nil
+}
+
+func (c *Client) Branches(ctx context.Context, repo string) ([]string, error) {
+	out, err := c.Run(ctx, "repo", "branch", "list", repo)
+	if err != nil {
+		return nil, err
+	}
+	return lines(out), nil
+}
+
+// Tree lists dir at ref, directories first. An empty ref means the default
+// branch.
+func (c *Client) Tree(ctx context.Context, repo, ref, dir string) ([]TreeEntry, error) {
+	out, err := c.Run(ctx, refArgs([]string{"repo", "tree", repo}, ref, dir)...)
+	if err != nil {
+		return nil, err
+	}
+	var entries []TreeEntry
+	for _, line := range lines(out) {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		e := TreeEntry{
+			Mode: strings.TrimSpace(fields[0]),
+			Size: strings.TrimSpace(fields[1]),
+			Name: path.Base(strings.TrimSpace(fields[2])),
+		}
+		e.Dir = strings.HasPrefix(e.Mode, "d")
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Dir && !entries[j].Dir
+	})
+	return entries, nil
+}
+
+// Blob returns the contents of file at ref.
+func (c *Client) Blob(ctx context.Context, repo, ref, file string) (string, error) {
+	return c.Run(ctx, refArgs([]string{"repo", "blob", repo}, ref, file)...)
+}
+
+// refArgs appends the optional [REFERENCE] [PATH] arguments of tree and
+// blob. With a single argument soft-serve takes it as the path, so a ref
+// always comes with a path; "." stands for the root, as empty arguments
+// don't survive the trip over SSH.
+func refArgs(args []string, ref, p string) []string {
+	switch {
+	case ref != "" && p == "":
+		return append(args, ref, ".")
+	case ref != "":
+		return append(args, ref, p)
+	case p != "":
+		return append(args, p)
+	default:
+		return args
+	}
+}
+
+// Commit returns soft-serve's description of a commit, which may be given as
+// a hash or a branch name.
+func (c *Client) Commit(ctx context.Context, repo, ref string) (string, error) {
+	return c.Run(ctx, "repo", "commit", repo, ref)
+}
+
+func lines(out string) []string {
+	var result []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
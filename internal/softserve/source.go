@@ -0,0 +1,58 @@
+package softserve
+
+import (
+	"context"
+	"errors"
+
+	"DragonTUI/internal/projects"
+)
+
+// readmeNames are tried in order when looking for a repository's README.
+var readmeNames = []string{"README.md", "readme.md", "README.markdown", "README"}
+
+// Source lists soft-serve repositories as projects. It embeds the Client so
+// the Projects page can also browse them.
+type Source struct {
+	*Client
+}
+
+func NewSource(client *Client) *Source {
+	return &Source{Client: client}
+}
+
+func (s *Source) Projects(ctx context.Context) ([]projects.Project, error) {
+	repos, err := s.Repos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]projects.Project, 0, len(repos))
+	for _, repo := range repos {
+		p := projects.Project{Name: repo}
+		// A repository we can list but not describe is still worth
+		// showing, so info errors other than a dead connection are
+		// ignored.
+		info, err := s.Info(ctx, repo)
+		var cmdErr *CommandError
+		switch {
+		case err == nil:
+			p.Description = info.Description
+		case errors.As(err, &cmdErr):
+		default:
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+func (s *Source) README(ctx context.Context, name string) (string, error) {
+	for _, file := range readmeNames {
+		md, err := s.Blob(ctx, name, "", file)
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) {
+			continue
+		}
+		return md, err
+	}
+	return "", projects.ErrNoReadme
+}
@@ -0,0 +1,109 @@
+package theme
+
+import (
+	"reflect"
+	"strings"
+
+	"DragonTUI/internal/utils"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// For returns t drawn by r, a session's renderer. Its styles are degraded to
+// the session's color profile and its markdown suits the session's
+// background. A renderer with the Ascii profile puts the theme in plain mode:
+// no color, blinking or box drawing, for screen readers and dumb terminals.
+func (t *Theme) For(r *lipgloss.Renderer) *Theme {
+	bound := *t
+	bound.r = r
+	return &bound
+}
+
+// Style returns an empty style drawn by the session's renderer. Pages use it
+// in place of lipgloss.NewStyle.
+func (t *Theme) Style() lipgloss.Style {
+	if t.r == nil {
+		return lipgloss.NewStyle()
+	}
+	return t.r.NewStyle()
+}
+
+// Profile is the color profile the session's terminal supports.
+func (t *Theme) Profile() termenv.Profile {
+	if t.r == nil {
+		return lipgloss.ColorProfile()
+	}
+	return t.r.ColorProfile()
+}
+
+// Plain reports whether the session is in plain mode.
+func (t *Theme) Plain() bool {
+	return t.Profile() == termenv.Ascii
+}
+
+// Markdown is the glamour style for the session: GlamourLight on a light
+// background, and notty, which only uses plain text, in plain mode.
+func (t *Theme) Markdown() string {
+	switch {
+	case t.Plain():
+		return "notty"
+	case t.r != nil && !t.r.HasDarkBackground():
+		return t.GlamourLight
+	}
+	return t.Glamour
+}
+
+// Rainbow draws s in the theme's blend. Terminals with fewer than 256 colors
+// can't show a smooth gradient, so they get s in the heading color instead.
+func (t *Theme) Rainbow(base lipgloss.Style, s string) string {
+	if t.Profile() >= termenv.ANSI {
+		return base.Foreground(t.Heading).Render(s)
+	}
+	return utils.Rainbow(base, s, t.blends)
+}
+
+// Outline is the border b, or an ASCII border in plain mode.
+func (t *Theme) Outline(b lipgloss.Border) lipgloss.Border {
+	if t.Plain() {
+		return lipgloss.ASCIIBorder()
+	}
+	return b
+}
+
+// Rule is a horizontal line width cells long.
+func (t *Theme) Rule(width int) string {
+	if t.Plain() {
+		return strings.Repeat("-", width)
+	}
+	return strings.Repeat("─", width)
+}
+
+var styleType = reflect.TypeOf(lipgloss.Style{})
+
+// Restyle binds every lipgloss.Style in the struct v points to, including
+// those in nested structs such as a bubbles component's Styles, to the
+// session's renderer. Styles in unexported fields are left alone.
+func (t *Theme) Restyle(v any) {
+	if t.r == nil {
+		return
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		restyle(rv.Elem(), t.r)
+	}
+}
+
+func restyle(v reflect.Value, r *lipgloss.Renderer) {
+	switch {
+	case v.Type() == styleType:
+		if v.CanSet() {
+			v.Set(reflect.ValueOf(v.Interface().(lipgloss.Style).Renderer(r)))
+		}
+	case v.Kind() == reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				restyle(v.Field(i), r)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Set is the themes a visitor can switch between, in the order they cycle
+// through.
+type Set struct {
+	themes []*Theme
+}
+
+// Load returns the built-in themes followed by the *.json themes in dir, if
+// dir is set. A theme file only needs the fields it changes; the rest are
+// taken from the default theme. A file named after a built-in theme
+// replaces it.
+func Load(dir string) (*Set, error) {
+	s := &Set{themes: append([]*Theme(nil), builtin...)}
+	if dir == "" {
+		return s, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		t, err := loadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		s.add(t)
+	}
+	return s, nil
+}
+
+func loadFile(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme: %w", err)
+	}
+	t := *builtin[0]
+	t.Name = ""
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("theme %s: %w", path, err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for _, hex := range append(t.Gradient[:], t.Blend[:]...) {
+		if _, err := colorful.Hex(hex); err != nil {
+			return nil, fmt.Errorf("theme %s: gradient and blend colors must be hex codes, not %q", path, hex)
+		}
+	}
+	t.prepare()
+	return &t, nil
+}
+
+func (s *Set) add(t *Theme) {
+	for i, existing := range s.themes {
+		if existing.Name == t.Name {
+			s.themes[i] = t
+			return
+		}
+	}
+	s.themes = append(s.themes, t)
+}
+
+// Get returns the theme called name.
+func (s *Set) Get(name string) (*Theme, bool) {
+	for _, t := range s.themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Next is the theme after t, wrapping around to the first.
+func (s *Set) Next(t *Theme) *Theme {
+	for i, existing := range s.themes {
+		if existing.Name == t.Name {
+			return s.themes[(i+1)%len(s.themes)]
+		}
+	}
+	return s.themes[0]
+}
+
+// Names lists the themes in order.
+func (s *Set) Names() []string {
+	names := make([]string, len(s.themes))
+	for i, t := range s.themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Default is the built-in default theme.
+func Default() *Theme {
+	return builtin[0]
+}
@@ -0,0 +1,198 @@
+// Package theme holds the palettes pages are drawn with. Every session has a
+// current theme, which the visitor can switch while connected; pages read
+// their colors from it instead of hard-coding them.
+package theme
+
+import (
+	"image/color"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/gamut"
+)
+
+// DefaultName is the theme sessions start with unless configured otherwise.
+const DefaultName = "dragon"
+
+// Theme is a named palette. Colors are hex codes such as #7f03fc, or ANSI
+// color numbers except where noted.
+type Theme struct {
+	Name string `json:"name"`
+	// Accent is the background of selected list items, banners and notices.
+	Accent lipgloss.Color `json:"accent"`
+	// OnAccent is text drawn on Accent.
+	OnAccent lipgloss.Color `json:"on_accent"`
+	// Text is emphasized text such as labels and key hints.
+	Text lipgloss.Color `json:"text"`
+	// Heading colors the text of boxed headings, and Border their border.
+	Heading lipgloss.Color `json:"heading"`
+	Border  lipgloss.Color `json:"border"`
+	// Muted is for secondary text and separators.
+	Muted lipgloss.Color `json:"muted"`
+	// Highlight draws the eye to spinners and scroll positions.
+	Highlight lipgloss.Color `json:"highlight"`
+	// Error colors error messages and the rule under page titles; Success
+	// the rule above page footers.
+	Error   lipgloss.Color `json:"error"`
+	Success lipgloss.Color `json:"success"`
+	// Gradient is the corners of the color grid: top left, top right,
+	// bottom left and bottom right. They must be hex codes.
+	Gradient [4]string `json:"gradient"`
+	// Blend is the two ends of the rainbow titles are drawn in. They must
+	// be hex codes.
+	Blend [2]string `json:"blend"`
+	// Glamour is the glamour style markdown is rendered in on dark
+	// backgrounds, such as dark or dracula, or the path of a glamour JSON
+	// style. GlamourLight is used on light backgrounds.
+	Glamour      string `json:"glamour"`
+	GlamourLight string `json:"glamour_light"`
+	// Code is the chroma style source files are highlighted in.
+	Code string `json:"code"`
+
+	blends []color.Color
+	// r draws the theme for one session; see For.
+	r *lipgloss.Renderer
+}
+
+var builtin = []*Theme{
+	{
+		Name:         "dragon",
+		Accent:       "#7f03fc",
+		OnAccent:     "#fffdf6",
+		Text:         "#fff8db",
+		Heading:      "#e60080",
+		Border:       "#643aff",
+		Muted:        "#335dcc",
+		Highlight:    "#edff83",
+		Error:        "#e60000",
+		Success:      "#009900",
+		Gradient:     [4]string{"#F25D94", "#EDFF82", "#643AFF", "#14F9D5"},
+		Blend:        [2]string{"#F25D94", "#EDFF82"},
+		Glamour:      "dracula",
+		GlamourLight: "light",
+		Code:         "dracula",
+	},
+	{
+		Name:         "forest",
+		Accent:       "#2d6a4f",
+		OnAccent:     "#f1faee",
+		Text:         "#d8f3dc",
+		Heading:      "#95d5b2",
+		Border:       "#40916c",
+		Muted:        "#74a57f",
+		Highlight:    "#ffd166",
+		Error:        "#e76f51",
+		Success:      "#52b788",
+		Gradient:     [4]string{"#1b4332", "#b7e4c7", "#774936", "#ffd166"},
+		Blend:        [2]string{"#52b788", "#ffd166"},
+		Glamour:      "dark",
+		GlamourLight: "light",
+		Code:         "monokai",
+	},
+	{
+		Name:         "ocean",
+		Accent:       "#005f73",
+		OnAccent:     "#e9f5f9",
+		Text:         "#e9d8a6",
+		Heading:      "#94d2bd",
+		Border:       "#0a9396",
+		Muted:        "#4c8dae",
+		Highlight:    "#ee9b00",
+		Error:        "#ae2012",
+		Success:      "#0a9396",
+		Gradient:     [4]string{"#001219", "#94d2bd", "#005f73", "#e9d8a6"},
+		Blend:        [2]string{"#0a9396", "#e9d8a6"},
+		Glamour:      "tokyo-night",
+		GlamourLight: "light",
+		Code:         "nord",
+	},
+	{
+		Name:         "paper",
+		Accent:       "#3b5bdb",
+		OnAccent:     "#ffffff",
+		Text:         "#212529",
+		Heading:      "#c2255c",
+		Border:       "#495057",
+		Muted:        "#868e96",
+		Highlight:    "#e67700",
+		Error:        "#c92a2a",
+		Success:      "#2b8a3e",
+		Gradient:     [4]string{"#c2255c", "#e67700", "#3b5bdb", "#0c8599"},
+		Blend:        [2]string{"#c2255c", "#3b5bdb"},
+		Glamour:      "light",
+		GlamourLight: "light",
+		Code:         "github",
+	},
+}
+
+func init() {
+	for _, t := range builtin {
+		t.prepare()
+	}
+}
+
+func (t *Theme) prepare() {
+	t.blends = gamut.Blends(lipgloss.Color(t.Blend[0]), lipgloss.Color(t.Blend[1]), 50)
+}
+
+// Grid blends the gradient's corners into ySteps rows of xSteps hex colors.
+func (t *Theme) Grid(xSteps, ySteps int) [][]string {
+	x0y0, _ := colorful.Hex(t.Gradient[0])
+	x1y0, _ := colorful.Hex(t.Gradient[1])
+	x0y1, _ := colorful.Hex(t.Gradient[2])
+	x1y1, _ := colorful.Hex(t.Gradient[3])
+
+	x0 := make([]colorful.Color, ySteps)
+	for i := range x0 {
+		x0[i] = x0y0.BlendLuv(x0y1, float64(i)/float64(ySteps))
+	}
+
+	x1 := make([]colorful.Color, ySteps)
+	for i := range x1 {
+		x1[i] = x1y0.BlendLuv(x1y1, float64(i)/float64(ySteps))
+	}
+
+	grid := make([][]string, ySteps)
+	for x := range make([]int, ySteps) {
+		y0 := x0[x]
+		grid[x] = make([]string, xSteps)
+		for y := range make([]int, xSteps) {
+			grid[x][y] = y0.BlendLuv(x1[x], float64(y)/float64(xSteps)).Hex()
+		}
+	}
+
+	return grid
+}
+
+var boxBorder = lipgloss.Border{
+	Top:          "▀",
+	Bottom:       "▄",
+	Left:         "█",
+	Right:        "█",
+	TopLeft:      "╔",
+	TopRight:     "╗",
+	BottomLeft:   "╚",
+	BottomRight:  "╝",
+	MiddleLeft:   "╠",
+	MiddleRight:  "╣",
+	Middle:       "╬",
+	MiddleTop:    "╦",
+	MiddleBottom: "╩",
+}
+
+// Box is the bordered style of headings and messages.
+func (t *Theme) Box() lipgloss.Style {
+	return t.Style().
+		Bold(true).
+		Foreground(t.Heading).
+		AlignHorizontal(lipgloss.Center).
+		MarginLeft(5).
+		Border(t.Outline(boxBorder)).
+		BorderForeground(t.Border).
+		Padding(1, 3)
+}
+
+// Faint is the style of help text and other quiet hints.
+func (t *Theme) Faint() lipgloss.Style {
+	return t.Box().Faint(true)
+}